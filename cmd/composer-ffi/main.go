@@ -0,0 +1,62 @@
+// Command composer-ffi exports the validation and version normalization
+// with a stable C ABI when built as a shared library.
+//
+// Build:
+//
+//	go build -buildmode=c-shared -o libcomposer.so ./cmd/composer-ffi
+//
+// The strings returned by the functions are JSON documents allocated
+// with malloc and must be released with composer_free.
+//
+// ABI version 1:
+//
+//	int   composer_abi_version(void);
+//	char* composer_validate(const char* content);
+//	char* composer_normalize(const char* version);
+//	void  composer_free(char* str);
+//
+// Example of usage from PHP:
+//
+//	$ffi = FFI::cdef("
+//	    char* composer_normalize(const char* version);
+//	    void composer_free(char* str);
+//	", "libcomposer.so");
+//	$res = $ffi->composer_normalize("v1.2.3-RC1");
+//	echo FFI::string($res); // {"version":"1.2.3.0-RC1"}
+//	$ffi->composer_free($res);
+package main
+
+// #include <stdlib.h>
+import "C"
+
+import (
+	"unsafe"
+
+	"github.com/i582/go-composer.json/internal/api"
+)
+
+// abiVersion must be increased on every incompatible
+// change of the exported functions.
+const abiVersion = 1
+
+//export composer_abi_version
+func composer_abi_version() C.int {
+	return abiVersion
+}
+
+//export composer_validate
+func composer_validate(content *C.char) *C.char {
+	return C.CString(api.Validate(C.GoString(content)))
+}
+
+//export composer_normalize
+func composer_normalize(version *C.char) *C.char {
+	return C.CString(api.Normalize(C.GoString(version)))
+}
+
+//export composer_free
+func composer_free(str *C.char) {
+	C.free(unsafe.Pointer(str))
+}
+
+func main() {}
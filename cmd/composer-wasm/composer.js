@@ -0,0 +1,36 @@
+// Thin wrapper around composer.wasm.
+//
+// Requires wasm_exec.js from the Go distribution
+// ($(go env GOROOT)/lib/wasm/wasm_exec.js, misc/wasm in older Go
+// versions) to be loaded first.
+//
+// Example:
+//
+//   const composer = await loadComposer("composer.wasm");
+//   composer.validate('{"name": "my/package"}'); // {valid: true, errors: [...]}
+//   composer.normalize("v1.2.3-RC1");             // "1.2.3.0-RC1"
+
+async function loadComposer(url) {
+  const go = new Go();
+  const result = await WebAssembly.instantiateStreaming(fetch(url), go.importObject);
+  go.run(result.instance);
+
+  const api = globalThis.goComposer;
+
+  return {
+    validate(content) {
+      return JSON.parse(api.validate(content));
+    },
+    normalize(version) {
+      const res = JSON.parse(api.normalize(version));
+      if (res.error) {
+        throw new Error(res.error);
+      }
+      return res.version;
+    },
+  };
+}
+
+if (typeof module !== "undefined") {
+  module.exports = { loadComposer };
+}
@@ -0,0 +1,46 @@
+//go:build js && wasm
+// +build js,wasm
+
+// Command composer-wasm exposes the validation and version normalization
+// to JavaScript when compiled to WebAssembly.
+//
+// Build:
+//
+//	GOOS=js GOARCH=wasm go build -o composer.wasm ./cmd/composer-wasm
+//
+// The functions are registered in the global goComposer object,
+// see composer.js for a thin wrapper.
+package main
+
+import (
+	"syscall/js"
+
+	"github.com/i582/go-composer.json/internal/api"
+)
+
+func main() {
+	js.Global().Set("goComposer", js.ValueOf(map[string]interface{}{
+		"validate":  js.FuncOf(validate),
+		"normalize": js.FuncOf(normalize),
+	}))
+
+	// Keep the functions available for the lifetime of the page.
+	select {}
+}
+
+// validate loads the composer.json passed as a string
+// and returns the errors as a JSON string.
+func validate(this js.Value, args []js.Value) interface{} {
+	if len(args) != 1 || args[0].Type() != js.TypeString {
+		return `{"valid":false,"errors":[{"message":"validate expects the content of composer.json","critical":true}]}`
+	}
+	return api.Validate(args[0].String())
+}
+
+// normalize returns the normalized version as a JSON string.
+func normalize(this js.Value, args []js.Value) interface{} {
+	if len(args) != 1 || args[0].Type() != js.TypeString {
+		return `{"error":"normalize expects a version string"}`
+	}
+	return api.Normalize(args[0].String())
+}
@@ -0,0 +1,70 @@
+// Package api contains the functions shared by the bindings of the
+// library for other languages (WebAssembly, C shared library).
+//
+// All functions accept and return strings, the results are JSON
+// documents, so that the bindings stay as thin as possible.
+package api
+
+import (
+	"encoding/json"
+
+	"github.com/i582/go-composer.json/pkg/composer"
+)
+
+// ValidateResult is the result of Validate.
+type ValidateResult struct {
+	Valid  bool    `json:"valid"`
+	Errors []Error `json:"errors"`
+}
+
+// Error is a single error of ValidateResult.
+type Error struct {
+	Message  string `json:"message"`
+	Critical bool   `json:"critical"`
+	Code     string `json:"code,omitempty"`
+	Pointer  string `json:"pointer,omitempty"`
+}
+
+// NormalizeResult is the result of Normalize.
+type NormalizeResult struct {
+	Version string `json:"version,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Validate loads the passed content of composer.json
+// and returns ValidateResult as JSON.
+func Validate(content string) string {
+	res := ValidateResult{Valid: true, Errors: []Error{}}
+
+	_, errs := composer.NewConfigFromData([]byte(content), "composer.json")
+	if errs != nil {
+		for _, e := range errs.Errors {
+			if e.Critical {
+				res.Valid = false
+			}
+			res.Errors = append(res.Errors, Error{
+				Message:  e.Msg,
+				Critical: e.Critical,
+				Code:     e.Code,
+				Pointer:  e.Pointer,
+			})
+		}
+	}
+
+	return toJSON(res)
+}
+
+// Normalize returns NormalizeResult with the normalized version as JSON.
+func Normalize(version string) string {
+	normalized, err := composer.NormalizeVersion(version)
+	if err != nil {
+		return toJSON(NormalizeResult{Error: err.Error()})
+	}
+
+	return toJSON(NormalizeResult{Version: normalized})
+}
+
+func toJSON(v interface{}) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
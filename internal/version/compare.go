@@ -0,0 +1,96 @@
+package version
+
+import (
+	"strings"
+)
+
+// Stability levels of versions in ascending order.
+const (
+	StabilityDev = iota
+	StabilityAlpha
+	StabilityBeta
+	StabilityRC
+	StabilityStable
+	StabilityPatch
+)
+
+// ParseStability returns the stability level by its name
+// as used in the minimum-stability field and stability flags.
+func ParseStability(name string) (int, bool) {
+	switch strings.ToLower(name) {
+	case "dev":
+		return StabilityDev, true
+	case "alpha":
+		return StabilityAlpha, true
+	case "beta":
+		return StabilityBeta, true
+	case "rc":
+		return StabilityRC, true
+	case "stable":
+		return StabilityStable, true
+	default:
+		return 0, false
+	}
+}
+
+// Stability returns the stability level of the version.
+//
+// Patch versions are considered more stable than
+// the version without a suffix, as in composer.
+func (v *Version) Stability() int {
+	switch {
+	case v.IsDev:
+		return StabilityDev
+	case v.IsAlpha:
+		return StabilityAlpha
+	case v.IsBeta:
+		return StabilityBeta
+	case v.IsRC:
+		return StabilityRC
+	case v.IsPatch:
+		return StabilityPatch
+	default:
+		return StabilityStable
+	}
+}
+
+// Compare returns -1, 0 or +1 depending on whether v is
+// less than, equal to or greater than other.
+//
+// The order of suffixes is the same as in composer:
+//
+//	dev < alpha < beta < RC < (no suffix) < patch
+//
+// and versions with the same suffix are ordered by the suffix
+// number, a suffix without a number is the lowest:
+//
+//	1.0.0-alpha < 1.0.0-alpha1 < 1.0.0-alpha2 < 1.0.0-beta
+func (v *Version) Compare(other *Version) int {
+	if res := compareInt(v.Major, other.Major); res != 0 {
+		return res
+	}
+	if res := compareInt(v.Minor, other.Minor); res != 0 {
+		return res
+	}
+	if res := compareInt(v.Micro, other.Micro); res != 0 {
+		return res
+	}
+	if res := compareInt(v.Build, other.Build); res != 0 {
+		return res
+	}
+	if res := compareInt(int64(v.Stability()), int64(other.Stability())); res != 0 {
+		return res
+	}
+	return compareInt(v.SuffixNumber, other.SuffixNumber)
+}
+
+func compareInt(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,47 @@
+package version
+
+import (
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	// The cases are taken from the composer/semver test suite.
+	tests := []struct {
+		A, B     string
+		Expected int
+	}{
+		{A: "1.0.0", B: "1.0.0", Expected: 0},
+		{A: "1.0.0", B: "1.0.1", Expected: -1},
+		{A: "1.1.0", B: "1.0.9", Expected: 1},
+		{A: "2.0.0", B: "1.99.99", Expected: 1},
+		{A: "1.0.0-patch", B: "1.0.0", Expected: 1},
+		{A: "1.0.0-RC", B: "1.0.0", Expected: -1},
+		{A: "1.0.0-dev", B: "1.0.0-alpha", Expected: -1},
+		{A: "1.0.0-alpha", B: "1.0.0-beta", Expected: -1},
+		{A: "1.0.0-beta", B: "1.0.0-RC", Expected: -1},
+		{A: "1.0.0-alpha", B: "1.0.0-alpha1", Expected: -1},
+		{A: "1.0.0-alpha1", B: "1.0.0-alpha2", Expected: -1},
+		{A: "1.0.0-alpha2", B: "1.0.0-beta1", Expected: -1},
+		{A: "1.0.0-beta2", B: "1.0.0-beta10", Expected: -1},
+		{A: "1.0.0-RC1", B: "1.0.0-RC2", Expected: -1},
+		{A: "1.0.0-RC2", B: "1.0.0", Expected: -1},
+		{A: "1.0.0-p1", B: "1.0.0-p2", Expected: -1},
+		{A: "1.0.0-pl1", B: "1.0.0-patch1", Expected: 0},
+		{A: "1.0.0-a1", B: "1.0.0-alpha1", Expected: 0},
+		{A: "1.0.0-b2", B: "1.0.0-beta2", Expected: 0},
+		{A: "1.0.0-patch", B: "1.0.1-dev", Expected: -1},
+		{A: "1.0.0-rc1", B: "1.0.0-RC1", Expected: 0},
+	}
+
+	for _, test := range tests {
+		a := mustVersion(t, test.A)
+		b := mustVersion(t, test.B)
+
+		if res := a.Compare(b); res != test.Expected {
+			t.Errorf("%s <=> %s: expected %d, got %d", test.A, test.B, test.Expected, res)
+		}
+		if res := b.Compare(a); res != -test.Expected {
+			t.Errorf("%s <=> %s: expected %d, got %d", test.B, test.A, -test.Expected, res)
+		}
+	}
+}
@@ -0,0 +1,647 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Constraint is a parsed composer version constraint.
+//
+// Internally the constraint is stored as a union of version ranges,
+// so that any combination of constraints can be represented.
+type Constraint struct {
+	raw    string
+	ranges []versionRange
+
+	// branches are the normalized names of the branches
+	// allowed by the constraint, for example, dev-master.
+	branches []string
+
+	// stability is the least stable of the stability flags,
+	// valid if hasStability is true.
+	stability    int
+	hasStability bool
+}
+
+// bound is one of the ends of a version range.
+type bound struct {
+	version   Version
+	inclusive bool
+	// unbounded means that the range has no limit on this side.
+	unbounded bool
+}
+
+// versionRange is a continuous range of versions.
+type versionRange struct {
+	lower bound
+	upper bound
+}
+
+// NewConstraint parses the composer version constraint.
+//
+// Constraints can be combined with logical operators as in composer:
+// AND is written as a comma or a space, OR as || (or the legacy |),
+// and AND binds tighter than OR. For example, ">=1.0 <1.1 || >=1.2"
+// is (>=1.0 AND <1.1) OR >=1.2.
+//
+// Supported forms:
+//
+//	1.2.3, =1.2.3, ==1.2.3  exact version
+//	>1.2, >=1.2, <2.0, <=2.0, !=1.2.3, <>1.2.3
+//	^1.2.3                  next significant release (>=1.2.3 <2.0.0)
+//	~1.2.3                  (>=1.2.3 <1.3.0)
+//	1.0 - 2.0               hyphen range (>=1.0.0 <2.1.0)
+//	*, 1.*, 1.2.*, 1.2.x    wildcard (1.2.* is >=1.2.0 <1.3.0)
+//	1, 1.2                  partial version, same as 1.*, 1.2.*
+//	dev-master, 1.0.x-dev   branch, see Constraint.AllowsBranch
+//
+// Versions may have a fourth part, as in 1.2.3.4. A constraint may
+// end with a stability flag, as in ^1.0@dev, which is recorded and
+// available with Constraint.StabilityFlag, @dev alone is *@dev.
+// The inline alias (1.0.0 as 1.2.0) and the commit reference of
+// a branch (dev-master#2eb0c09) are ignored, as in composer.
+//
+// The self.version constraint is not allowed, see NewConstraintFor.
+func NewConstraint(val string) (*Constraint, error) {
+	return NewConstraintFor(val, nil)
+}
+
+// NewConstraintFor parses the version constraint from the config
+// of the package with the passed version.
+//
+// Unlike NewConstraint, the self.version constraint is allowed and
+// means exactly the version of the package, as in composer. If the
+// version is nil, self.version is reported as an error.
+func NewConstraintFor(val string, self *Version) (*Constraint, error) {
+	val = strings.TrimSpace(val)
+	if val == "" {
+		return nil, fmt.Errorf("constraint is empty")
+	}
+
+	c := &Constraint{raw: val}
+	for _, orPart := range orSeparator.Split(val, -1) {
+		if orPart == "" {
+			return nil, fmt.Errorf("constraint '%s' contains an empty alternative", val)
+		}
+
+		orRanges, err := c.parseAndConstraint(orPart, self)
+		if err != nil {
+			return nil, err
+		}
+
+		c.ranges = append(c.ranges, orRanges...)
+	}
+
+	return c, nil
+}
+
+// orSeparator matches the || operator and the legacy | operator.
+var orSeparator = regexp.MustCompile(`\s*\|\|?\s*`)
+
+// operatorOnly matches a token that consists only of an operator,
+// for example, ">=" in ">= 1.0".
+var operatorOnly = regexp.MustCompile(`^(<>|!=|>=|<=|==|=|<|>|\^|~)$`)
+
+// parseAndConstraint parses constraints separated by commas or spaces,
+// the result allows only the versions that satisfy all of them.
+//
+// A branch constraint cannot be combined with other constraints,
+// it is recorded in the branches of the constraint.
+func (c *Constraint) parseAndConstraint(val string, self *Version) ([]versionRange, error) {
+	var ranges = []versionRange{{lower: bound{unbounded: true}, upper: bound{unbounded: true}}}
+	var branch string
+	var count int
+
+	for _, operand := range strings.Split(val, ",") {
+		tokens := strings.Fields(operand)
+		if len(tokens) == 0 {
+			return nil, fmt.Errorf("constraint '%s' contains an empty operand", val)
+		}
+
+		for i := 0; i < len(tokens); i++ {
+			count++
+
+			token := tokens[i]
+			if i+1 < len(tokens) && tokens[i+1] == "-" {
+				if i+2 == len(tokens) {
+					return nil, fmt.Errorf("hyphen range in constraint '%s' has no upper version", val)
+				}
+
+				hyphenRange, err := parseHyphenRange(token, tokens[i+2])
+				if err != nil {
+					return nil, err
+				}
+
+				ranges = intersectRanges(ranges, hyphenRange)
+				i += 2
+				continue
+			}
+			if operatorOnly.MatchString(token) {
+				if i+1 == len(tokens) {
+					return nil, fmt.Errorf("operator '%s' in constraint '%s' has no version", token, val)
+				}
+				i++
+				token += tokens[i]
+			}
+			// The alias is only used by composer when resolving
+			// dependencies, the constraint is the aliased version.
+			if i+1 < len(tokens) && tokens[i+1] == "as" {
+				if i+2 == len(tokens) {
+					return nil, fmt.Errorf("alias in constraint '%s' has no version", val)
+				}
+				i += 2
+			}
+
+			token, err := c.stripStabilityFlag(token)
+			if err != nil {
+				return nil, err
+			}
+
+			if name, ok := parseBranchConstraint(token); ok {
+				branch = name
+				continue
+			}
+
+			if token == "self.version" {
+				if self == nil {
+					return nil, fmt.Errorf("self.version in constraint '%s' requires the version of the package", val)
+				}
+				ranges = intersectRanges(ranges, []versionRange{{
+					lower: bound{version: *self, inclusive: true},
+					upper: bound{version: *self, inclusive: true},
+				}})
+				continue
+			}
+
+			partRanges, err := parseSingleConstraint(token)
+			if err != nil {
+				return nil, err
+			}
+
+			ranges = intersectRanges(ranges, partRanges)
+		}
+	}
+
+	if branch != "" {
+		if count > 1 {
+			return nil, fmt.Errorf("branch constraint in '%s' cannot be combined with other constraints", val)
+		}
+		c.branches = append(c.branches, branch)
+		return nil, nil
+	}
+
+	return ranges, nil
+}
+
+// stripStabilityFlag removes the stability flag from the constraint
+// and records it, a flag without a constraint means any version.
+func (c *Constraint) stripStabilityFlag(val string) (string, error) {
+	idx := strings.LastIndex(val, "@")
+	if idx == -1 {
+		return val, nil
+	}
+
+	stability, ok := ParseStability(val[idx+1:])
+	if !ok {
+		return "", fmt.Errorf("unknown stability flag '%s' in constraint '%s'", val[idx+1:], val)
+	}
+
+	if !c.hasStability || stability < c.stability {
+		c.stability = stability
+		c.hasStability = true
+	}
+
+	if idx == 0 {
+		return "*", nil
+	}
+	return val[:idx], nil
+}
+
+// parseBranchConstraint returns the normalized name of the branch
+// if the constraint refers to a branch, as dev-master or 1.0.x-dev.
+//
+// The commit reference after # is ignored.
+func parseBranchConstraint(val string) (string, bool) {
+	if idx := strings.Index(val, "#"); idx != -1 {
+		val = val[:idx]
+	}
+
+	normalized, err := Normalize(val)
+	if err != nil || !isBranchVersion(normalized) {
+		return "", false
+	}
+	return normalized, true
+}
+
+// isBranchVersion reports whether the normalized version is a branch.
+func isBranchVersion(normalized string) bool {
+	return strings.HasPrefix(normalized, "dev-") || strings.HasSuffix(normalized, "9999999-dev")
+}
+
+// intersectRanges returns the ranges of versions contained
+// in both sets of ranges.
+func intersectRanges(a, b []versionRange) []versionRange {
+	var res []versionRange
+	for _, ra := range a {
+		for _, rb := range b {
+			r := ra.intersect(rb)
+			if !r.isEmpty() {
+				res = append(res, r)
+			}
+		}
+	}
+	return res
+}
+
+// String returns the constraint as it was passed to NewConstraint.
+func (c *Constraint) String() string {
+	return c.raw
+}
+
+// StabilityFlag returns the stability of the flag passed with the
+// constraint, for example, StabilityDev for ^1.0@dev.
+//
+// If there are several flags, the least stable one is returned.
+func (c *Constraint) StabilityFlag() (stability int, ok bool) {
+	return c.stability, c.hasStability
+}
+
+// Branches returns the normalized names of the branches allowed by
+// the constraint, for example, dev-master and 1.0.9999999.9999999-dev.
+func (c *Constraint) Branches() []string {
+	return append([]string(nil), c.branches...)
+}
+
+// AllowsBranch reports whether the constraint allows the branch.
+//
+// The name may be given in any form accepted by composer, for example,
+// master and dev-master are the same branch, as 1.0.x and 1.0.x-dev.
+func (c *Constraint) AllowsBranch(name string) bool {
+	normalized, err := Normalize(name)
+	if err != nil || !isBranchVersion(normalized) {
+		normalized = NormalizeBranch(name)
+	}
+
+	for _, branch := range c.branches {
+		if branch == normalized {
+			return true
+		}
+	}
+	return false
+}
+
+// Allows reports whether the version satisfies the constraint.
+func (c *Constraint) Allows(v *Version) bool {
+	for _, r := range c.ranges {
+		if r.contains(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsMajor reports whether the constraint allows
+// at least one version with the passed major version.
+func (c *Constraint) AllowsMajor(major int64) bool {
+	majorRange := versionRange{
+		lower: bound{version: lowest(major, 0, 0), inclusive: true},
+		upper: bound{version: lowest(major+1, 0, 0)},
+	}
+	for _, r := range c.ranges {
+		if !r.intersect(majorRange).isEmpty() {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowedMajors returns the sorted list of major versions
+// allowed by the constraint.
+//
+// If the constraint has no upper limit, openEnded is true, and all
+// major versions after the last returned one are also allowed.
+func (c *Constraint) AllowedMajors() (majors []int64, openEnded bool) {
+	var last int64
+	for _, r := range c.ranges {
+		if r.upper.unbounded {
+			openEnded = true
+		}
+		if !r.upper.unbounded && r.upper.version.Major > last {
+			last = r.upper.version.Major
+		}
+		if !r.lower.unbounded && r.lower.version.Major > last {
+			last = r.lower.version.Major
+		}
+	}
+
+	for major := int64(0); major <= last; major++ {
+		if c.AllowsMajor(major) {
+			majors = append(majors, major)
+		}
+	}
+
+	return majors, openEnded
+}
+
+// LowerBound returns the lowest bound of the versions allowed by the
+// constraint, the bound itself may be excluded, as in >1.0.
+//
+// If the constraint has no lower limit, as <2.0, or allows
+// only branches, ok is false.
+func (c *Constraint) LowerBound() (v Version, ok bool) {
+	for i, r := range c.ranges {
+		if r.lower.unbounded {
+			return Version{}, false
+		}
+		if i == 0 || r.lower.version.Compare(&v) < 0 {
+			v = r.lower.version
+		}
+	}
+	return v, len(c.ranges) != 0
+}
+
+// contains reports whether the version is within the range.
+func (r versionRange) contains(v *Version) bool {
+	if !r.lower.unbounded {
+		res := v.Compare(&r.lower.version)
+		if res < 0 || res == 0 && !r.lower.inclusive {
+			return false
+		}
+	}
+	if !r.upper.unbounded {
+		res := v.Compare(&r.upper.version)
+		if res > 0 || res == 0 && !r.upper.inclusive {
+			return false
+		}
+	}
+	return true
+}
+
+// intersect returns the range of versions contained in both ranges.
+func (r versionRange) intersect(other versionRange) versionRange {
+	res := r
+
+	if res.lower.unbounded {
+		res.lower = other.lower
+	} else if !other.lower.unbounded {
+		cmp := other.lower.version.Compare(&res.lower.version)
+		if cmp > 0 || cmp == 0 && !other.lower.inclusive {
+			res.lower = other.lower
+		}
+	}
+
+	if res.upper.unbounded {
+		res.upper = other.upper
+	} else if !other.upper.unbounded {
+		cmp := other.upper.version.Compare(&res.upper.version)
+		if cmp < 0 || cmp == 0 && !other.upper.inclusive {
+			res.upper = other.upper
+		}
+	}
+
+	return res
+}
+
+// isEmpty reports whether the range contains no versions.
+func (r versionRange) isEmpty() bool {
+	if r.lower.unbounded || r.upper.unbounded {
+		return false
+	}
+	cmp := r.lower.version.Compare(&r.upper.version)
+	if cmp > 0 {
+		return true
+	}
+	return cmp == 0 && !(r.lower.inclusive && r.upper.inclusive)
+}
+
+// lowest returns the lowest possible version with the passed
+// numbers, that is, the dev version.
+func lowest(major, minor, micro int64) Version {
+	return Version{Major: major, Minor: minor, Micro: micro, IsDev: true}
+}
+
+// parseSingleConstraint parses a constraint without logical operators.
+func parseSingleConstraint(val string) ([]versionRange, error) {
+	switch {
+	case strings.HasPrefix(val, "^"):
+		return parseCaret(val[1:])
+	case strings.HasPrefix(val, "~"):
+		return parseTilde(val[1:])
+	case val == "*" || val == "x" || val == "X":
+		return []versionRange{{lower: bound{unbounded: true}, upper: bound{unbounded: true}}}, nil
+	}
+
+	if wildcard := strings.TrimRight(val, ".*xX"); wildcard != val && strings.HasSuffix(val[:len(wildcard)+1], ".") {
+		return parseWildcard(wildcard)
+	}
+
+	op := ""
+	for _, candidate := range []string{">=", "<=", "<>", "!=", "==", ">", "<", "="} {
+		if strings.HasPrefix(val, candidate) {
+			op = candidate
+			break
+		}
+	}
+
+	v, parts, err := parsePartialVersion(strings.TrimSpace(val[len(op):]))
+	if err != nil {
+		return nil, err
+	}
+
+	if op == "" && parts < 3 && !v.HasPrefix() {
+		return parseWildcard(strings.TrimPrefix(val, "v"))
+	}
+
+	switch op {
+	case ">=":
+		// As in composer, >=1.0 also allows 1.0.0-dev and other
+		// pre-release versions of 1.0.0 unless the suffix is explicit.
+		if !v.HasPrefix() {
+			v.IsDev = true
+		}
+		return []versionRange{{lower: bound{version: v, inclusive: true}, upper: bound{unbounded: true}}}, nil
+	case ">":
+		return []versionRange{{lower: bound{version: v}, upper: bound{unbounded: true}}}, nil
+	case "<":
+		if !v.HasPrefix() {
+			v.IsDev = true
+		}
+		return []versionRange{{lower: bound{unbounded: true}, upper: bound{version: v}}}, nil
+	case "<=":
+		return []versionRange{{lower: bound{unbounded: true}, upper: bound{version: v, inclusive: true}}}, nil
+	case "!=", "<>":
+		return []versionRange{
+			{lower: bound{unbounded: true}, upper: bound{version: v}},
+			{lower: bound{version: v}, upper: bound{unbounded: true}},
+		}, nil
+	}
+
+	return []versionRange{{lower: bound{version: v, inclusive: true}, upper: bound{version: v, inclusive: true}}}, nil
+}
+
+// parseHyphenRange parses the hyphen range "from - to".
+//
+// The lower bound is inclusive. The upper bound is inclusive
+// if it is a full version, otherwise the last specified part may
+// increase: 1.0 - 2.0 is >=1.0.0 <2.1.0, 1.0 - 2 is >=1.0.0 <3.0.0.
+func parseHyphenRange(from, to string) ([]versionRange, error) {
+	lower, _, err := parsePartialVersion(from)
+	if err != nil {
+		return nil, err
+	}
+	if !lower.HasPrefix() {
+		lower.IsDev = true
+	}
+
+	upper, parts, err := parsePartialVersion(to)
+	if err != nil {
+		return nil, err
+	}
+
+	if parts >= 3 || upper.HasPrefix() {
+		return []versionRange{{
+			lower: bound{version: lower, inclusive: true},
+			upper: bound{version: upper, inclusive: true},
+		}}, nil
+	}
+
+	if parts == 1 {
+		upper = lowest(upper.Major+1, 0, 0)
+	} else {
+		upper = lowest(upper.Major, upper.Minor+1, 0)
+	}
+
+	return []versionRange{{
+		lower: bound{version: lower, inclusive: true},
+		upper: bound{version: upper},
+	}}, nil
+}
+
+// parseWildcard parses the version before the wildcard,
+// the last specified part of the version may increase:
+// 1.2.* is >=1.2.0 <1.3.0, 1.* is >=1.0.0 <2.0.0.
+func parseWildcard(val string) ([]versionRange, error) {
+	v, parts, err := parsePartialVersion(val)
+	if err != nil {
+		return nil, err
+	}
+	if v.HasPrefix() {
+		return nil, fmt.Errorf("wildcard version '%s' cannot have a suffix", val)
+	}
+
+	var upper Version
+	switch parts {
+	case 1:
+		upper = lowest(v.Major+1, 0, 0)
+	case 2:
+		upper = lowest(v.Major, v.Minor+1, 0)
+	default:
+		upper = lowest(v.Major, v.Minor, v.Micro+1)
+	}
+
+	v.IsDev = true
+	return []versionRange{{lower: bound{version: v, inclusive: true}, upper: bound{version: upper}}}, nil
+}
+
+// parseCaret parses the ^ operator.
+//
+// ^1.2.3 is >=1.2.3 <2.0.0, for versions before 1.0 the minor
+// version acts as the major one: ^0.3 is >=0.3.0 <0.4.0.
+func parseCaret(val string) ([]versionRange, error) {
+	v, parts, err := parsePartialVersion(strings.TrimSpace(val))
+	if err != nil {
+		return nil, err
+	}
+
+	var upper Version
+	switch {
+	case v.Major != 0 || parts == 1:
+		upper = lowest(v.Major+1, 0, 0)
+	case v.Minor != 0 || parts == 2:
+		upper = lowest(0, v.Minor+1, 0)
+	default:
+		upper = lowest(0, 0, v.Micro+1)
+	}
+
+	if !v.HasPrefix() {
+		v.IsDev = true
+	}
+
+	return []versionRange{{lower: bound{version: v, inclusive: true}, upper: bound{version: upper}}}, nil
+}
+
+// parseTilde parses the ~ operator.
+//
+// The last specified part of the version may increase:
+// ~1.2 is >=1.2.0 <2.0.0, ~1.2.3 is >=1.2.3 <1.3.0,
+// ~1.2.3.4 is >=1.2.3.4 <1.2.4.0.
+func parseTilde(val string) ([]versionRange, error) {
+	v, parts, err := parsePartialVersion(strings.TrimSpace(val))
+	if err != nil {
+		return nil, err
+	}
+
+	var upper Version
+	switch parts {
+	case 1, 2:
+		upper = lowest(v.Major+1, 0, 0)
+	case 3:
+		upper = lowest(v.Major, v.Minor+1, 0)
+	default:
+		upper = lowest(v.Major, v.Minor, v.Micro+1)
+	}
+
+	if !v.HasPrefix() {
+		v.IsDev = true
+	}
+
+	return []versionRange{{lower: bound{version: v, inclusive: true}, upper: bound{version: upper}}}, nil
+}
+
+// parsePartialVersion parses a version in which the minor and
+// micro parts may be omitted, for example, 1 or 1.2, and which
+// may have the fourth part, for example, 1.2.3.4.
+//
+// Returns the version and the number of specified parts.
+func parsePartialVersion(val string) (Version, int, error) {
+	var version Version
+
+	val = strings.TrimPrefix(val, "v")
+	if val == "" {
+		return version, 0, fmt.Errorf("version in constraint is empty")
+	}
+
+	if idx := strings.Index(val, "-"); idx != -1 {
+		suffix := val[idx+1:]
+		val = val[:idx]
+
+		full, err := NewVersion("0.0.0-" + suffix)
+		if err != nil {
+			return version, 0, err
+		}
+		version = *full
+	}
+
+	parts := strings.Split(val, ".")
+	if len(parts) > 4 {
+		return version, 0, fmt.Errorf("version '%s' must have at most 4 parts", val)
+	}
+
+	nums := make([]int64, 4)
+	for i, part := range parts {
+		num, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return version, 0, fmt.Errorf("part %d ('%s') of the version must be a number", i+1, part)
+		}
+		nums[i] = num
+	}
+
+	version.Major = nums[0]
+	version.Minor = nums[1]
+	version.Micro = nums[2]
+	version.Build = nums[3]
+
+	return version, len(parts), nil
+}
@@ -0,0 +1,443 @@
+package version
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConstraintAllows(t *testing.T) {
+	tests := []allowsTest{
+		{
+			Constraint: "1.2.3",
+			Allowed:    []string{"1.2.3"},
+			Disallowed: []string{"1.2.4", "1.2.3-dev", "1.2.3-patch"},
+		},
+		{
+			Constraint: ">=1.2",
+			Allowed:    []string{"1.2.0-dev", "1.2.0", "5.0.0"},
+			Disallowed: []string{"1.1.9"},
+		},
+		{
+			Constraint: ">=1.2.0-beta",
+			Allowed:    []string{"1.2.0-RC", "1.2.0"},
+			Disallowed: []string{"1.2.0-alpha"},
+		},
+		{
+			Constraint: "<2.0",
+			Allowed:    []string{"1.9.9"},
+			Disallowed: []string{"2.0.0-dev", "2.0.0-RC", "2.0.0"},
+		},
+		{
+			Constraint: "!=1.2.3",
+			Allowed:    []string{"1.2.2", "1.2.4"},
+			Disallowed: []string{"1.2.3"},
+		},
+		{
+			Constraint: "^1.2.3",
+			Allowed:    []string{"1.2.3", "1.9.0"},
+			Disallowed: []string{"1.2.2", "2.0.0-dev", "2.0.0"},
+		},
+		{
+			Constraint: "^0.3",
+			Allowed:    []string{"0.3.0", "0.3.9"},
+			Disallowed: []string{"0.4.0", "1.0.0"},
+		},
+		{
+			Constraint: "~1.2",
+			Allowed:    []string{"1.2.0", "1.9.0"},
+			Disallowed: []string{"2.0.0"},
+		},
+		{
+			Constraint: "~1.2.3",
+			Allowed:    []string{"1.2.3", "1.2.9"},
+			Disallowed: []string{"1.3.0"},
+		},
+	}
+
+	runAllowsTests(t, tests)
+}
+
+func TestConstraintAllowedMajors(t *testing.T) {
+	tests := []struct {
+		Constraint string
+		Majors     []int64
+		OpenEnded  bool
+	}{
+		{Constraint: "^1.2", Majors: []int64{1}},
+		{Constraint: "^0.3", Majors: []int64{0}},
+		{Constraint: ">=1.0", Majors: []int64{1}, OpenEnded: true},
+		{Constraint: "<3.0", Majors: []int64{0, 1, 2}},
+		{Constraint: "<=3.0", Majors: []int64{0, 1, 2, 3}},
+		{Constraint: "2.5.0", Majors: []int64{2}},
+	}
+
+	for _, test := range tests {
+		c, err := NewConstraint(test.Constraint)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.Constraint, err)
+		}
+
+		majors, openEnded := c.AllowedMajors()
+		if !reflect.DeepEqual(majors, test.Majors) || openEnded != test.OpenEnded {
+			t.Errorf("%s: expected %v (open ended: %v), got %v (open ended: %v)",
+				test.Constraint, test.Majors, test.OpenEnded, majors, openEnded)
+		}
+	}
+}
+
+type allowsTest struct {
+	Constraint string
+	Allowed    []string
+	Disallowed []string
+}
+
+func runAllowsTests(t *testing.T, tests []allowsTest) {
+	t.Helper()
+	for _, test := range tests {
+		c, err := NewConstraint(test.Constraint)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.Constraint, err)
+		}
+
+		for _, raw := range test.Allowed {
+			if !c.Allows(mustVersion(t, raw)) {
+				t.Errorf("%s: expected %s to be allowed", test.Constraint, raw)
+			}
+		}
+		for _, raw := range test.Disallowed {
+			if c.Allows(mustVersion(t, raw)) {
+				t.Errorf("%s: expected %s to be disallowed", test.Constraint, raw)
+			}
+		}
+	}
+}
+
+func mustVersion(t *testing.T, raw string) *Version {
+	t.Helper()
+	v, err := NewVersion(raw)
+	if err != nil {
+		t.Fatalf("%s: unexpected error: %v", raw, err)
+	}
+	return v
+}
+
+func TestCompositeConstraints(t *testing.T) {
+	tests := []allowsTest{
+		{
+			Constraint: ">=1.0 <1.1 || >=1.2",
+			Allowed:    []string{"1.0.0", "1.0.9", "1.2.0", "3.0.0"},
+			Disallowed: []string{"0.9.0", "1.1.0", "1.1.5"},
+		},
+		{
+			Constraint: ">=1.0,<1.1 || >=1.2",
+			Allowed:    []string{"1.0.5", "1.2.0"},
+			Disallowed: []string{"1.1.0"},
+		},
+		{
+			Constraint: ">=1.0, <2.0",
+			Allowed:    []string{"1.0.0", "1.9.9"},
+			Disallowed: []string{"0.9.9", "2.0.0"},
+		},
+		{
+			Constraint: ">= 1.0 < 2.0",
+			Allowed:    []string{"1.5.0"},
+			Disallowed: []string{"2.0.0"},
+		},
+		{
+			Constraint: "^1.0 || ^2.0",
+			Allowed:    []string{"1.0.0", "2.5.0"},
+			Disallowed: []string{"0.9.0", "3.0.0"},
+		},
+		{
+			Constraint: "^1.0 | ^2.0",
+			Allowed:    []string{"1.0.0", "2.5.0"},
+			Disallowed: []string{"3.0.0"},
+		},
+		{
+			Constraint: "^1.0||^2.0",
+			Allowed:    []string{"1.0.0", "2.5.0"},
+			Disallowed: []string{"3.0.0"},
+		},
+		{
+			Constraint: "1.0.0 || 1.0.2",
+			Allowed:    []string{"1.0.0", "1.0.2"},
+			Disallowed: []string{"1.0.1"},
+		},
+		{
+			Constraint: ">1.0 <3.0 !=2.0.0",
+			Allowed:    []string{"1.0.1", "2.0.1"},
+			Disallowed: []string{"1.0.0", "2.0.0", "3.0.0"},
+		},
+		{
+			Constraint: "<1.0 >2.0",
+			Disallowed: []string{"0.5.0", "1.5.0", "2.5.0"},
+		},
+	}
+
+	runAllowsTests(t, tests)
+
+	for _, invalid := range []string{"^1.0 ||", "|| ^1.0", ">=", "1.0 || >= "} {
+		if _, err := NewConstraint(invalid); err == nil {
+			t.Errorf("%s: expected an error", invalid)
+		}
+	}
+}
+
+func TestHyphenRangeConstraints(t *testing.T) {
+	tests := []allowsTest{
+		{
+			Constraint: "1.0 - 2.0",
+			Allowed:    []string{"1.0.0-dev", "1.0.0", "2.0.5"},
+			Disallowed: []string{"0.9.9", "2.1.0-dev", "2.1.0"},
+		},
+		{
+			Constraint: "1.0.0 - 2.1.0",
+			Allowed:    []string{"1.0.0", "2.1.0"},
+			Disallowed: []string{"2.1.1"},
+		},
+		{
+			Constraint: "1.0 - 2",
+			Allowed:    []string{"2.9.9"},
+			Disallowed: []string{"3.0.0"},
+		},
+		{
+			Constraint: "1.0 - 2.0 || 3.0 - 3.1",
+			Allowed:    []string{"1.5.0", "3.1.5"},
+			Disallowed: []string{"2.5.0", "3.2.0"},
+		},
+		{
+			Constraint: "1.0 - 2.0 !=1.5.0",
+			Allowed:    []string{"1.4.0"},
+			Disallowed: []string{"1.5.0"},
+		},
+	}
+
+	runAllowsTests(t, tests)
+
+	if _, err := NewConstraint("1.0 -"); err == nil {
+		t.Errorf("expected an error for a hyphen range without upper version")
+	}
+}
+
+func TestWildcardConstraints(t *testing.T) {
+	runAllowsTests(t, []allowsTest{
+		{
+			Constraint: "*",
+			Allowed:    []string{"0.0.1-dev", "1.0.0", "99.0.0"},
+		},
+		{
+			Constraint: "1.2.*",
+			Allowed:    []string{"1.2.0-dev", "1.2.0", "1.2.99"},
+			Disallowed: []string{"1.1.9", "1.3.0-dev", "1.3.0"},
+		},
+		{
+			Constraint: "2.*",
+			Allowed:    []string{"2.0.0", "2.9.0"},
+			Disallowed: []string{"1.9.9", "3.0.0"},
+		},
+		{
+			Constraint: "1.2.x",
+			Allowed:    []string{"1.2.5"},
+			Disallowed: []string{"1.3.0"},
+		},
+		{
+			Constraint: "2.1",
+			Allowed:    []string{"2.1.0", "2.1.9"},
+			Disallowed: []string{"2.0.9", "2.2.0"},
+		},
+		{
+			Constraint: "2",
+			Allowed:    []string{"2.0.0", "2.9.9"},
+			Disallowed: []string{"3.0.0"},
+		},
+		{
+			Constraint: "=2.1",
+			Allowed:    []string{"2.1.0"},
+			Disallowed: []string{"2.1.1"},
+		},
+		{
+			Constraint: "1.* || 3.*",
+			Allowed:    []string{"1.5.0", "3.5.0"},
+			Disallowed: []string{"2.5.0"},
+		},
+	})
+
+	for _, invalid := range []string{"1.2.*-beta", "a.*"} {
+		if _, err := NewConstraint(invalid); err == nil {
+			t.Errorf("%s: expected an error", invalid)
+		}
+	}
+}
+
+func TestConstraintFlagsAndBranches(t *testing.T) {
+	runAllowsTests(t, []allowsTest{
+		{
+			Constraint: "^1.0@dev",
+			Allowed:    []string{"1.0.0-dev", "1.5.0"},
+			Disallowed: []string{"2.0.0"},
+		},
+		{
+			Constraint: "*@dev",
+			Allowed:    []string{"0.0.1-dev", "3.0.0"},
+		},
+		{
+			Constraint: "@dev",
+			Allowed:    []string{"3.0.0"},
+		},
+		{
+			Constraint: "1.0.0 as 1.2.0",
+			Allowed:    []string{"1.0.0"},
+			Disallowed: []string{"1.2.0"},
+		},
+		{
+			Constraint: "~1.0 || dev-main",
+			Allowed:    []string{"1.5.0"},
+			Disallowed: []string{"2.0.0"},
+		},
+	})
+
+	for _, test := range []struct {
+		Constraint string
+		Stability  int
+		Flagged    bool
+	}{
+		{Constraint: "^1.0@dev", Stability: StabilityDev, Flagged: true},
+		{Constraint: "^1.0@beta || ^2.0@alpha", Stability: StabilityAlpha, Flagged: true},
+		{Constraint: "^1.0"},
+	} {
+		c, err := NewConstraint(test.Constraint)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.Constraint, err)
+		}
+		stability, ok := c.StabilityFlag()
+		if stability != test.Stability || ok != test.Flagged {
+			t.Errorf("%s: expected stability %d (%v), got %d (%v)", test.Constraint, test.Stability, test.Flagged, stability, ok)
+		}
+	}
+
+	for _, test := range []struct {
+		Constraint string
+		Allowed    []string
+		Disallowed []string
+	}{
+		{Constraint: "dev-master", Allowed: []string{"master", "dev-master"}, Disallowed: []string{"main"}},
+		{Constraint: "dev-master#2eb0c09", Allowed: []string{"master"}},
+		{Constraint: "1.0.x-dev", Allowed: []string{"1.0.x", "1.0.x-dev", "1.0"}, Disallowed: []string{"1.1.x"}},
+		{Constraint: "~1.0 || dev-main", Allowed: []string{"main"}, Disallowed: []string{"master"}},
+	} {
+		c, err := NewConstraint(test.Constraint)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.Constraint, err)
+		}
+		for _, branch := range test.Allowed {
+			if !c.AllowsBranch(branch) {
+				t.Errorf("%s: expected branch %s to be allowed", test.Constraint, branch)
+			}
+		}
+		for _, branch := range test.Disallowed {
+			if c.AllowsBranch(branch) {
+				t.Errorf("%s: expected branch %s to be disallowed", test.Constraint, branch)
+			}
+		}
+		if len(c.Branches()) != 1 {
+			t.Errorf("%s: expected a single branch, got %v", test.Constraint, c.Branches())
+		}
+	}
+
+	for _, invalid := range []string{"^1.0,", "^1.0,,^1.1", ",^1.0", "^1.0@unknown", "1.0.0 as", "dev-master >=1.0"} {
+		if _, err := NewConstraint(invalid); err == nil {
+			t.Errorf("%s: expected an error", invalid)
+		}
+	}
+}
+
+func TestFourPartConstraints(t *testing.T) {
+	tests := []struct {
+		Constraint string
+		Allowed    []Version
+		Disallowed []Version
+	}{
+		{
+			Constraint: "1.2.3.4",
+			Allowed:    []Version{{Major: 1, Minor: 2, Micro: 3, Build: 4}},
+			Disallowed: []Version{{Major: 1, Minor: 2, Micro: 3}, {Major: 1, Minor: 2, Micro: 3, Build: 5}},
+		},
+		{
+			Constraint: "~1.2.3.4",
+			Allowed:    []Version{{Major: 1, Minor: 2, Micro: 3, Build: 9}},
+			Disallowed: []Version{{Major: 1, Minor: 2, Micro: 3, Build: 3}, {Major: 1, Minor: 2, Micro: 4}},
+		},
+		{
+			Constraint: ">=1.2.3.4 <2.0",
+			Allowed:    []Version{{Major: 1, Minor: 9}},
+			Disallowed: []Version{{Major: 1, Minor: 2, Micro: 3}},
+		},
+	}
+
+	for _, test := range tests {
+		c, err := NewConstraint(test.Constraint)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.Constraint, err)
+		}
+		for i := range test.Allowed {
+			if !c.Allows(&test.Allowed[i]) {
+				t.Errorf("%s: expected %+v to be allowed", test.Constraint, test.Allowed[i])
+			}
+		}
+		for i := range test.Disallowed {
+			if c.Allows(&test.Disallowed[i]) {
+				t.Errorf("%s: expected %+v to be disallowed", test.Constraint, test.Disallowed[i])
+			}
+		}
+	}
+}
+
+func TestSelfVersionConstraint(t *testing.T) {
+	self := mustVersion(t, "1.2.0")
+
+	c, err := NewConstraintFor("self.version || ^2.0", self)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, raw := range []string{"1.2.0", "2.1.0"} {
+		if !c.Allows(mustVersion(t, raw)) {
+			t.Errorf("expected %s to be allowed", raw)
+		}
+	}
+	if c.Allows(mustVersion(t, "1.2.1")) {
+		t.Errorf("expected 1.2.1 to be disallowed")
+	}
+
+	if _, err := NewConstraint("self.version"); err == nil {
+		t.Errorf("expected an error for self.version without the version of the package")
+	}
+}
+
+func TestConstraintLowerBound(t *testing.T) {
+	tests := []struct {
+		Constraint string
+		Expected   string
+		Ok         bool
+	}{
+		{Constraint: "^7.4 || ^8.0", Expected: "7.4.0-dev", Ok: true},
+		{Constraint: ">=8.1.2", Expected: "8.1.2-dev", Ok: true},
+		{Constraint: "^8.0 || <7.0"},
+		{Constraint: "dev-master"},
+	}
+
+	for _, test := range tests {
+		c, err := NewConstraint(test.Constraint)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.Constraint, err)
+		}
+
+		v, ok := c.LowerBound()
+		if ok != test.Ok {
+			t.Errorf("%s: expected ok to be %v", test.Constraint, test.Ok)
+			continue
+		}
+		if ok && v.Compare(mustVersion(t, test.Expected)) != 0 {
+			t.Errorf("%s: expected %s, got %+v", test.Constraint, test.Expected, v)
+		}
+	}
+}
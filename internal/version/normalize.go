@@ -0,0 +1,148 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// modifierRegexp is the part of the regexps below that
+// matches the stability modifier, as in composer.
+const modifierRegexp = `[._-]?(?:(stable|beta|b|RC|alpha|a|patch|pl|p)((?:[.-]?\d+)*)?)?([.-]?dev)?`
+
+var (
+	aliasRegexp         = regexp.MustCompile(`^([^,\s]+) +as +([^,\s]+)$`)
+	stabilityFlagRegexp = regexp.MustCompile(`(?i)@(?:stable|RC|beta|alpha|dev)$`)
+	buildMetadataRegexp = regexp.MustCompile(`^([^,\s+]+)\+[^\s]+$`)
+	classicalRegexp     = regexp.MustCompile(`(?i)^v?(\d{1,5})(\.\d+)?(\.\d+)?(\.\d+)?` + modifierRegexp + `$`)
+	dateRegexp          = regexp.MustCompile(`(?i)^v?(\d{4}(?:[.:-]?\d{2}){1,6}(?:[.:-]?\d{1,3}){0,2})` + modifierRegexp + `$`)
+	devBranchRegexp     = regexp.MustCompile(`(?i)^(.*?)[.-]?dev$`)
+	numericBranchRegexp = regexp.MustCompile(`(?i)^v?(\d+)(\.(?:\d+|[xX*]))?(\.(?:\d+|[xX*]))?(\.(?:\d+|[xX*]))?$`)
+	nonDigitRegexp      = regexp.MustCompile(`\D`)
+)
+
+// Normalize returns the version in the normalized form used by
+// composer, for example, in composer.lock.
+//
+// The result is the same as the result of
+// Composer\Semver\VersionParser::normalize:
+//
+//	1.0         -> 1.0.0.0
+//	v1.2.3-RC1  -> 1.2.3.0-RC1
+//	1.0.0-b2    -> 1.0.0.0-beta2
+//	master      -> dev-master
+//	dev-feature -> dev-feature
+//	2.x-dev     -> 2.9999999.9999999.9999999-dev
+func Normalize(val string) (string, error) {
+	val = strings.TrimSpace(val)
+	orig := val
+
+	// Strip off aliasing.
+	if matches := aliasRegexp.FindStringSubmatch(val); matches != nil {
+		val = matches[1]
+	}
+
+	// Strip off stability flag.
+	if loc := stabilityFlagRegexp.FindStringIndex(val); loc != nil {
+		val = val[:loc[0]]
+	}
+
+	// Normalize master/trunk/default branches to dev-name.
+	switch val {
+	case "master", "trunk", "default":
+		val = "dev-" + val
+	}
+
+	if strings.HasPrefix(strings.ToLower(val), "dev-") {
+		return "dev-" + val[4:], nil
+	}
+
+	// Strip off build metadata.
+	if matches := buildMetadataRegexp.FindStringSubmatch(val); matches != nil {
+		val = matches[1]
+	}
+
+	var matches []string
+	var index int
+
+	if matches = classicalRegexp.FindStringSubmatch(val); matches != nil {
+		val = matches[1]
+		for _, part := range matches[2:5] {
+			if part == "" {
+				part = ".0"
+			}
+			val += part
+		}
+		index = 5
+	} else if matches = dateRegexp.FindStringSubmatch(val); matches != nil {
+		val = nonDigitRegexp.ReplaceAllString(matches[1], ".")
+		index = 2
+	}
+
+	if matches != nil {
+		if matches[index] != "" {
+			if matches[index] == "stable" {
+				return val, nil
+			}
+			val += "-" + expandStability(matches[index]) + strings.TrimLeft(matches[index+1], ".-")
+		}
+
+		if matches[index+2] != "" {
+			val += "-dev"
+		}
+
+		return val, nil
+	}
+
+	// Match dev branches.
+	if matches := devBranchRegexp.FindStringSubmatch(val); matches != nil {
+		normalized := NormalizeBranch(matches[1])
+		// A branch ending with -dev is only valid if it is numeric.
+		if !strings.Contains(normalized, "dev-") {
+			return normalized, nil
+		}
+	}
+
+	return "", fmt.Errorf("invalid version string '%s'", orig)
+}
+
+// NormalizeBranch returns the normalized name of the branch
+// as Composer\Semver\VersionParser::normalizeBranch does.
+//
+// Numeric branches are converted to the version form (2.1.x is
+// 2.1.9999999.9999999-dev), other branches are prefixed with dev-.
+func NormalizeBranch(name string) string {
+	name = strings.TrimSpace(name)
+
+	matches := numericBranchRegexp.FindStringSubmatch(name)
+	if matches == nil {
+		return "dev-" + name
+	}
+
+	var version string
+	for _, part := range matches[1:5] {
+		if part == "" {
+			part = ".x"
+		}
+		version += strings.NewReplacer("*", "x", "X", "x").Replace(part)
+	}
+
+	return strings.ReplaceAll(version, "x", "9999999") + "-dev"
+}
+
+// expandStability returns the full name of the stability.
+func expandStability(stability string) string {
+	stability = strings.ToLower(stability)
+	switch stability {
+	case "a":
+		return "alpha"
+	case "b":
+		return "beta"
+	case "p", "pl":
+		return "patch"
+	case "rc":
+		return "RC"
+	default:
+		return stability
+	}
+}
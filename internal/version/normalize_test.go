@@ -0,0 +1,93 @@
+package version
+
+import (
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	// The cases are taken from the composer/semver test suite.
+	tests := []struct {
+		Version  string
+		Expected string
+	}{
+		{Version: "1.0.0", Expected: "1.0.0.0"},
+		{Version: "1.2.3.4", Expected: "1.2.3.4"},
+		{Version: "1.0.0RC1dev", Expected: "1.0.0.0-RC1-dev"},
+		{Version: "1.0.0-rC15-dev", Expected: "1.0.0.0-RC15-dev"},
+		{Version: "1.0.0.RC.15-dev", Expected: "1.0.0.0-RC15-dev"},
+		{Version: "1.0.0-rc1", Expected: "1.0.0.0-RC1"},
+		{Version: "1.0.0-pl3", Expected: "1.0.0.0-patch3"},
+		{Version: "1.0", Expected: "1.0.0.0"},
+		{Version: "0", Expected: "0.0.0.0"},
+		{Version: "10.4.13-beta", Expected: "10.4.13.0-beta"},
+		{Version: "10.4.13beta2", Expected: "10.4.13.0-beta2"},
+		{Version: "10.4.13beta.2", Expected: "10.4.13.0-beta2"},
+		{Version: "10.4.13-b", Expected: "10.4.13.0-beta"},
+		{Version: "10.4.13-b5", Expected: "10.4.13.0-beta5"},
+		{Version: "v1.0.0", Expected: "1.0.0.0"},
+		{Version: "v1.2.3-RC1", Expected: "1.2.3.0-RC1"},
+		{Version: "v20100102", Expected: "20100102"},
+		{Version: "2010.01", Expected: "2010.01.0.0"},
+		{Version: "2010.01.02", Expected: "2010.01.02.0"},
+		{Version: "2010.01.02", Expected: "2010.01.02.0"},
+		{Version: "2010-01-02.5", Expected: "2010.01.02.5"},
+		{Version: "20100102-203040", Expected: "20100102.203040"},
+		{Version: "20100102203040-10", Expected: "20100102203040.10"},
+		{Version: "20100102-203040-p1", Expected: "20100102.203040-patch1"},
+		{Version: "1.0.0-stable", Expected: "1.0.0.0"},
+		{Version: "1.0.0+foo", Expected: "1.0.0.0"},
+		{Version: "1.0.0-alpha.3.1+foo/-bar", Expected: "1.0.0.0-alpha3.1"},
+		{Version: "dev-master", Expected: "dev-master"},
+		{Version: "master", Expected: "dev-master"},
+		{Version: "trunk", Expected: "dev-trunk"},
+		{Version: "dev-feature-foo", Expected: "dev-feature-foo"},
+		{Version: "DEV-FOOBAR", Expected: "dev-FOOBAR"},
+		{Version: "dev-feature/foo", Expected: "dev-feature/foo"},
+		{Version: "1.x-dev", Expected: "1.9999999.9999999.9999999-dev"},
+		{Version: "2.1.x-dev", Expected: "2.1.9999999.9999999-dev"},
+		{Version: "1.0.0 as 1.0.x-dev", Expected: "1.0.0.0"},
+		{Version: "1.0.0@dev", Expected: "1.0.0.0"},
+		{Version: " 1.0.0", Expected: "1.0.0.0"},
+	}
+
+	for _, test := range tests {
+		res, err := Normalize(test.Version)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.Version, err)
+			continue
+		}
+		if res != test.Expected {
+			t.Errorf("%s: expected %s, got %s", test.Version, test.Expected, res)
+		}
+	}
+
+	for _, invalid := range []string{"", "a", "1.0.0-meh", "1.0.0.0.0", "feature-foo-dev", "1.0 .2"} {
+		if res, err := Normalize(invalid); err == nil {
+			t.Errorf("%s: expected an error, got %s", invalid, res)
+		}
+	}
+}
+
+func TestNormalizeBranch(t *testing.T) {
+	tests := []struct {
+		Branch   string
+		Expected string
+	}{
+		{Branch: "v1.x", Expected: "1.9999999.9999999.9999999-dev"},
+		{Branch: "v1.*", Expected: "1.9999999.9999999.9999999-dev"},
+		{Branch: "v1.0", Expected: "1.0.9999999.9999999-dev"},
+		{Branch: "2.0", Expected: "2.0.9999999.9999999-dev"},
+		{Branch: "v1.0.x", Expected: "1.0.9999999.9999999-dev"},
+		{Branch: "v1.0.3.*", Expected: "1.0.3.9999999-dev"},
+		{Branch: "v2.4.0", Expected: "2.4.0.9999999-dev"},
+		{Branch: "2.4.4", Expected: "2.4.4.9999999-dev"},
+		{Branch: "master", Expected: "dev-master"},
+		{Branch: "feature-a", Expected: "dev-feature-a"},
+	}
+
+	for _, test := range tests {
+		if res := NormalizeBranch(test.Branch); res != test.Expected {
+			t.Errorf("%s: expected %s, got %s", test.Branch, test.Expected, res)
+		}
+	}
+}
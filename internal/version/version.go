@@ -2,6 +2,7 @@ package version
 
 import (
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 )
@@ -10,14 +11,25 @@ type Version struct {
 	Major int64
 	Minor int64
 	Micro int64
+	// Build is the optional fourth part of the version,
+	// for example, 4 for 1.2.3.4.
+	Build int64
 
 	IsDev   bool
 	IsPatch bool
 	IsAlpha bool
 	IsBeta  bool
 	IsRC    bool
+
+	// SuffixNumber is the number after the suffix,
+	// for example, 3 for 1.0.0-alpha3.
+	SuffixNumber int64
 }
 
+// suffixRegexp matches the version suffix with an optional number,
+// for example, alpha, beta2 or RC.1.
+var suffixRegexp = regexp.MustCompile(`(?i)^(dev|patch|pl|p|alpha|a|beta|b|rc)\.?(\d+)?$`)
+
 func NewVersion(val string) (*Version, error) {
 	var version = &Version{}
 
@@ -36,19 +48,30 @@ func NewVersion(val string) (*Version, error) {
 	}
 
 	if len(vals) == 2 {
-		switch vals[1] {
+		matches := suffixRegexp.FindStringSubmatch(vals[1])
+		if matches == nil || strings.EqualFold(matches[1], "dev") && matches[2] != "" {
+			return nil, fmt.Errorf("unknown version suffix '%s'", vals[1])
+		}
+
+		switch strings.ToLower(matches[1]) {
 		case "dev":
 			version.IsDev = true
-		case "patch", "p":
+		case "patch", "pl", "p":
 			version.IsPatch = true
 		case "alpha", "a":
 			version.IsAlpha = true
 		case "beta", "b":
 			version.IsBeta = true
-		case "RC":
+		case "rc":
 			version.IsRC = true
-		default:
-			return nil, fmt.Errorf("unknown version suffix '%s'", vals[1])
+		}
+
+		if matches[2] != "" {
+			num, err := strconv.ParseInt(matches[2], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("suffix number ('%s') of the version must be a number", matches[2])
+			}
+			version.SuffixNumber = num
 		}
 	}
 
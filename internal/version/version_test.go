@@ -100,6 +100,40 @@ func TestVersion(t *testing.T) {
 			},
 		},
 
+		{
+			Version: "1.0.0-alpha3",
+			Expected: Version{
+				Major:        1,
+				IsAlpha:      true,
+				SuffixNumber: 3,
+			},
+		},
+		{
+			Version: "1.0.0-RC5",
+			Expected: Version{
+				Major:        1,
+				IsRC:         true,
+				SuffixNumber: 5,
+			},
+		},
+		{
+			Version: "v2.0.4-p1",
+			Expected: Version{
+				Major:        2,
+				Micro:        4,
+				IsPatch:      true,
+				SuffixNumber: 1,
+			},
+		},
+		{
+			Version: "1.0.0-rc.2",
+			Expected: Version{
+				Major:        1,
+				IsRC:         true,
+				SuffixNumber: 2,
+			},
+		},
+
 		// Errors
 
 		{
@@ -120,7 +154,12 @@ func TestVersion(t *testing.T) {
 		{
 			Version:  "1.0.0-unknown_suffix",
 			Expected: Version{},
-			Error:    fmt.Errorf("unknown version siffix 'unknown_suffix'"),
+			Error:    fmt.Errorf("unknown version suffix 'unknown_suffix'"),
+		},
+		{
+			Version:  "1.0.0-dev1",
+			Expected: Version{},
+			Error:    fmt.Errorf("unknown version suffix 'dev1'"),
 		},
 		{
 			Version:  "1.0.0.0",
@@ -0,0 +1,161 @@
+package composer
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Archive is a structure for storing the archive section,
+// the options for creating package archives.
+type Archive struct {
+	// The base name of the archive, by default
+	// (if not provided and not passed on the CLI),
+	// the package name is used.
+	Name string `json:"name"`
+	// A list of patterns for paths to exclude. The pattern syntax
+	// matches .gitignore files: a leading exclamation mark (!) will
+	// result in any matching files to be included even if a previous
+	// pattern excluded them, a leading slash will only match at the
+	// beginning of the project relative path, an asterisk will not
+	// expand to a directory separator.
+	//
+	// Example:
+	//   "exclude": ["/foo/bar", "baz", "/*.test", "!/foo/bar/baz"]
+	Exclude []string `json:"exclude"`
+}
+
+// IsExcluded returns true if the file with the passed path, relative
+// to the config root, is excluded from the archive.
+//
+// As in composer, the patterns are applied in order
+// and the last matching pattern wins.
+func (a Archive) IsExcluded(path string) bool {
+	path = "/" + strings.TrimPrefix(filepath.ToSlash(path), "/")
+
+	excluded := false
+	for _, pattern := range a.Exclude {
+		re, negate := archivePatternRegexp(pattern)
+		if re != nil && re.MatchString(path) {
+			excluded = !negate
+		}
+	}
+	return excluded
+}
+
+// vcsDirs are the dirs composer never puts into archives.
+var vcsDirs = map[string]bool{
+	".git": true,
+	".svn": true,
+	".hg":  true,
+	".bzr": true,
+	"CVS":  true,
+}
+
+// ArchiveFiles returns the paths, relative to the config root, of
+// the files that end up in a dist archive of the package.
+//
+// Only the exclude patterns of the archive section and the VCS
+// dirs are taken into account, export-ignore attributes and
+// .gitignore files are not.
+func (c *Config) ArchiveFiles() ([]string, error) {
+	var files []string
+
+	err := filepath.Walk(c.RootDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			if vcsDirs[info.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		rel, err := filepath.Rel(c.RootDir, path)
+		if err != nil {
+			return err
+		}
+
+		if !c.Archive.IsExcluded(rel) {
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+
+	return files, err
+}
+
+// archivePatternRegexp converts the exclude pattern into a regexp
+// matched against the path with a leading slash, as composer's
+// BaseExcludeFilter::generatePattern does.
+func archivePatternRegexp(pattern string) (re *regexp.Regexp, negate bool) {
+	pattern = strings.TrimSpace(pattern)
+	if strings.HasPrefix(pattern, "!") {
+		negate = true
+		pattern = pattern[1:]
+	}
+	if pattern == "" {
+		return nil, negate
+	}
+
+	// A pattern with a leading slash is anchored to the root, a pattern
+	// without slashes, or with only a trailing one, matches a name at any
+	// level, and a pattern with an inner slash is not anchored at all.
+	var prefix string
+	switch slash := strings.IndexByte(pattern, '/'); {
+	case slash == 0:
+		prefix = "^/"
+	case slash < 0 || slash == len(pattern)-1:
+		prefix = "/"
+	}
+	pattern = strings.Trim(pattern, "/")
+
+	re, err := regexp.Compile(prefix + globToRegexp(pattern) + "(/|$)")
+	if err != nil {
+		return nil, negate
+	}
+	return re, negate
+}
+
+// globToRegexp converts the glob into a regexp where
+// an asterisk does not match a directory separator
+// and a double asterisk matches any number of dirs.
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		switch ch := glob[i]; ch {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				i++
+				if i+1 < len(glob) && glob[i+1] == '/' {
+					i++
+					b.WriteString("(.*/)?")
+				} else {
+					b.WriteString(".*")
+				}
+				continue
+			}
+			b.WriteString("[^/]*")
+		case '?':
+			b.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(glob[i:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end
+		default:
+			b.WriteString(regexp.QuoteMeta(string(ch)))
+		}
+	}
+	return b.String()
+}
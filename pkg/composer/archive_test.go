@@ -0,0 +1,71 @@
+package composer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestArchiveExclude(t *testing.T) {
+	archive := Archive{
+		Exclude: []string{"/foo/bar", "baz", "/*.test", "!/foo/bar/baz", "docs/**/*.md", "/tests/", "/build/", "src/Fixtures"},
+	}
+
+	tests := []struct {
+		path     string
+		excluded bool
+	}{
+		{path: "foo/bar/file.php", excluded: true},
+		{path: "foo/bar/baz/file.php", excluded: false},
+		{path: "src/baz/file.php", excluded: true},
+		{path: "src/bazooka.php", excluded: false},
+		{path: "unit.test", excluded: true},
+		{path: "src/unit.test", excluded: false},
+		{path: "src/docs/a/b/readme.md", excluded: true},
+		{path: "src/file.php", excluded: false},
+		{path: "tests/ATest.php", excluded: true},
+		{path: "src/tests/ATest.php", excluded: false},
+		{path: "build/out.txt", excluded: true},
+		{path: "builder.php", excluded: false},
+		{path: "src/Fixtures/a.php", excluded: true},
+		{path: "lib/src/Fixtures/a.php", excluded: true},
+	}
+
+	for _, tt := range tests {
+		if excluded := archive.IsExcluded(tt.path); excluded != tt.excluded {
+			t.Errorf("IsExcluded(%q) = %v, want %v", tt.path, excluded, tt.excluded)
+		}
+	}
+}
+
+func TestArchiveFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "composer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, file := range []string{"composer.json", "src/A.php", "tests/ATest.php", ".git/HEAD"} {
+		path := filepath.Join(dir, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config, _ := NewConfigFromData([]byte(`{"version": "1.0.0", "archive": {"exclude": ["/tests"]}}`), filepath.Join(dir, "composer.json"))
+
+	files, err := config.ArchiveFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"composer.json", "src/A.php"}
+	if !reflect.DeepEqual(files, expected) {
+		t.Errorf("mismatch archive files:\nwant: %v\nhave: %v", expected, files)
+	}
+}
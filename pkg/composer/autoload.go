@@ -0,0 +1,153 @@
+package composer
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// CodeInvalidAutoload is the code of the errors reported
+// for malformed autoload mappings by AutoloadPathsCheck.
+const CodeInvalidAutoload = "invalid-autoload"
+
+// CodeMissingAutoloadPath is the code of the errors reported
+// for autoload paths that do not exist by AutoloadPathsCheck.
+const CodeMissingAutoloadPath = "missing-autoload-path"
+
+// AutoloadPathsCheck is a check provider that validates the psr-4,
+// files and classmap mappings of autoload and autoload-dev, and
+// reports the paths that do not exist.
+//
+// The paths of generated code, that only exist after code generation
+// has run, can be marked as generated, either in the check itself or
+// in the config:
+//
+//	"extra": {
+//	    "composer-check": {
+//	        "generated": ["src/Generated/"]
+//	    }
+//	}
+//
+// The mappings to generated paths and the paths inside them are still
+// validated, only the existence check is skipped.
+type AutoloadPathsCheck struct {
+	// Generated is a list of paths, relative to the config root,
+	// that contain generated code.
+	Generated []string
+}
+
+// CheckAutoloadPaths is a check for Config.AddCheckProvider that
+// validates the autoload mappings and their paths without generated
+// paths other than the ones marked in the config.
+//
+// See AutoloadPathsCheck
+func CheckAutoloadPaths(c *Config) []*ConfigError {
+	return AutoloadPathsCheck{}.Check(c)
+}
+
+// Check implements the CheckProvider interface.
+func (a AutoloadPathsCheck) Check(c *Config) []*ConfigError {
+	generated := append([]string{}, a.Generated...)
+	generated = append(generated, c.generatedPaths()...)
+
+	var errors []*ConfigError
+	errors = append(errors, checkAutoload(c, "autoload", &c.Autoload, generated)...)
+	errors = append(errors, checkAutoload(c, "autoload-dev", &c.AutoloadDev, generated)...)
+	return errors
+}
+
+// generatedPaths returns the extra."composer-check".generated list.
+func (c *Config) generatedPaths() []string {
+	var raw struct {
+		Generated []string `json:"generated"`
+	}
+	if err := c.ExtraInto("composer-check", &raw); err != nil {
+		return nil
+	}
+	return raw.Generated
+}
+
+func checkAutoload(c *Config, section string, autoload *Autoload, generated []string) []*ConfigError {
+	var errors []*ConfigError
+
+	checkPath := func(p string, pointer string) {
+		switch {
+		case strings.TrimSpace(p) == "":
+			errors = append(errors, &ConfigError{
+				Msg:      fmt.Sprintf("%s: path is empty", section),
+				Critical: false,
+				Code:     CodeInvalidAutoload,
+				Pointer:  pointer,
+			})
+		case isGeneratedPath(p, generated):
+		case !c.pathExists(p):
+			errors = append(errors, &ConfigError{
+				Msg:      fmt.Sprintf("%s: path '%s' does not exist", section, p),
+				Critical: false,
+				Code:     CodeMissingAutoloadPath,
+				Pointer:  pointer,
+			})
+		}
+	}
+
+	for _, prefix := range autoload.Psr4.Prefixes() {
+		pointer := "/" + section + "/psr-4/" + escapePointer(prefix)
+		if prefix != "" && !strings.HasSuffix(prefix, `\`) {
+			errors = append(errors, &ConfigError{
+				Msg:      fmt.Sprintf(`%s: psr-4 prefix %s must end with a namespace separator, use "%s\\"`, section, prefix, prefix),
+				Critical: false,
+				Code:     CodeInvalidAutoload,
+				Pointer:  pointer,
+			})
+		}
+		paths := autoload.Psr4[prefix]
+		if len(paths) == 1 {
+			checkPath(paths[0], pointer)
+			continue
+		}
+		for i, path := range paths {
+			checkPath(path, pointer+"/"+strconv.Itoa(i))
+		}
+	}
+
+	for i, file := range autoload.Files {
+		checkPath(file, "/"+section+"/files/"+strconv.Itoa(i))
+	}
+
+	for i, entry := range autoload.Classmap {
+		checkPath(entry, "/"+section+"/classmap/"+strconv.Itoa(i))
+	}
+
+	return errors
+}
+
+// pathExists reports whether the autoload path relative to the config
+// root exists, the target-dir prefix is handled as in RootAutoloadPath.
+func (c *Config) pathExists(p string) bool {
+	return c.fileExists(c.RootAutoloadPath(p))
+}
+
+// fileExists reports whether the path relative to the config root exists.
+func (c *Config) fileExists(p string) bool {
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(c.RootDir, filepath.FromSlash(p))
+	}
+	_, err := os.Stat(p)
+	return err == nil
+}
+
+// isGeneratedPath reports whether the path is one
+// of the generated paths or is inside one of them.
+func isGeneratedPath(p string, generated []string) bool {
+	p = path.Clean(filepath.ToSlash(p))
+	for _, dir := range generated {
+		dir = path.Clean(filepath.ToSlash(dir))
+		if p == dir || strings.HasPrefix(p, dir+"/") || dir == "." {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package composer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestAutoloadPathsCheck(t *testing.T) {
+	dir, err := ioutil.TempDir("", "composer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := os.MkdirAll(filepath.Join(dir, "src"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	config, _ := NewConfigFromData([]byte(`{
+		"version": "1.0.0",
+		"autoload": {
+			"psr-4": {"App\\": "src/", "App\\Generated\\": "gen/Proxies/", "Broken": "src/"},
+			"files": ["helpers.php"]
+		},
+		"autoload-dev": {"classmap": ["build/"]},
+		"extra": {"composer-check": {"generated": ["gen"]}}
+	}`), filepath.Join(dir, "composer.json"))
+
+	var pointers []string
+	for _, err := range (AutoloadPathsCheck{Generated: []string{"build"}}).Check(config) {
+		pointers = append(pointers, err.Code+" "+err.Pointer)
+	}
+
+	expected := []string{
+		"invalid-autoload /autoload/psr-4/Broken",
+		"missing-autoload-path /autoload/files/0",
+	}
+	if !reflect.DeepEqual(pointers, expected) {
+		t.Errorf("mismatch errors:\nwant: %v\nhave: %v", expected, pointers)
+	}
+
+	if errs := CheckAutoloadPaths(config); len(errs) != 3 {
+		t.Errorf("expected build/ to be reported without the policy, got %v", errs)
+	}
+}
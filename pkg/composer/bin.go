@@ -0,0 +1,62 @@
+package composer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Binaries is a list of binaries of the package.
+//
+// In composer.json the bin field is either a string
+// or an array of strings, both forms are stored as a list.
+type Binaries []string
+
+// UnmarshalJSON implements the json.Unmarshaler interface
+// for both the string and the array forms.
+func (b *Binaries) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*b = Binaries{single}
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("bin must be a string or an array of strings")
+	}
+
+	*b = list
+	return nil
+}
+
+// Binary is a binary of the package resolved by Config.ResolveBinaries.
+type Binary struct {
+	// Name is the path as written in the bin field.
+	Name string
+	// Path is the absolute path to the binary.
+	Path string
+	// Exists is true if the binary is a regular file.
+	Exists bool
+}
+
+// ResolveBinaries returns the binaries of the bin field
+// with the paths relative to the config root.
+func (c *Config) ResolveBinaries() []Binary {
+	binaries := make([]Binary, 0, len(c.Bin))
+	for _, name := range c.Bin {
+		path := name
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(c.RootDir, path)
+		}
+
+		info, err := os.Stat(path)
+		binaries = append(binaries, Binary{
+			Name:   name,
+			Path:   path,
+			Exists: err == nil && info.Mode().IsRegular(),
+		})
+	}
+	return binaries
+}
@@ -0,0 +1,39 @@
+package composer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestResolveBinaries(t *testing.T) {
+	dir, err := ioutil.TempDir("", "composer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := os.MkdirAll(filepath.Join(dir, "bin"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "bin", "tool"), []byte("#!/usr/bin/env php"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	config, _ := NewConfigFromData([]byte(`{"version": "1.0.0", "bin": ["bin/tool", "bin/missing"]}`), filepath.Join(dir, "composer.json"))
+
+	expected := []Binary{
+		{Name: "bin/tool", Path: filepath.Join(dir, "bin", "tool"), Exists: true},
+		{Name: "bin/missing", Path: filepath.Join(dir, "bin", "missing"), Exists: false},
+	}
+	if binaries := config.ResolveBinaries(); !reflect.DeepEqual(binaries, expected) {
+		t.Errorf("mismatch binaries:\nwant: %v\nhave: %v", expected, binaries)
+	}
+
+	config, _ = NewConfigFromData([]byte(`{"version": "1.0.0", "bin": "bin/tool"}`), filepath.Join(dir, "composer.json"))
+	if !reflect.DeepEqual(config.Bin, Binaries{"bin/tool"}) {
+		t.Errorf("unexpected bin: %v", config.Bin)
+	}
+}
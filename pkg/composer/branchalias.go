@@ -0,0 +1,88 @@
+package composer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/i582/go-composer.json/internal/version"
+)
+
+// CodeInvalidBranchAlias is the code of the errors reported
+// by CheckBranchAliases.
+const CodeInvalidBranchAlias = "invalid-branch-alias"
+
+// BranchAliases returns the valid aliases of extra.branch-alias,
+// the keys are the dev branches and the values are the versions
+// they represent.
+//
+// Example:
+//
+//	"extra": {
+//	    "branch-alias": {
+//	        "dev-main": "2.x-dev"
+//	    }
+//	}
+//
+// Invalid aliases are skipped, as in composer, see CheckBranchAliases.
+func (c *Config) BranchAliases() map[string]string {
+	aliases := map[string]string{}
+	for branch, alias := range c.rawBranchAliases() {
+		if validateBranchAlias(branch, alias) == nil {
+			aliases[branch] = alias
+		}
+	}
+	return aliases
+}
+
+// CheckBranchAliases is a check for Config.AddCheckProvider that
+// reports the aliases of extra.branch-alias that composer ignores.
+//
+// See CheckProviderFunc
+func CheckBranchAliases(c *Config) []*ConfigError {
+	raw := c.rawBranchAliases()
+
+	var errors []*ConfigError
+	for _, branch := range sortedKeys(raw) {
+		err := validateBranchAlias(branch, raw[branch])
+		if err == nil {
+			continue
+		}
+
+		errors = append(errors, &ConfigError{
+			Msg:      err.Error(),
+			Critical: false,
+			Code:     CodeInvalidBranchAlias,
+			Pointer:  "/extra/branch-alias/" + escapePointer(branch),
+		})
+	}
+
+	return errors
+}
+
+// rawBranchAliases returns extra.branch-alias as is.
+func (c *Config) rawBranchAliases() map[string]string {
+	var raw map[string]string
+	if err := c.ExtraInto("branch-alias", &raw); err != nil {
+		return nil
+	}
+	return raw
+}
+
+// validateBranchAlias checks that the branch is a dev branch and
+// the alias is a numeric branch version like 2.x-dev or 1.0.x-dev.
+func validateBranchAlias(branch, alias string) error {
+	if !strings.HasPrefix(branch, "dev-") && !strings.HasSuffix(branch, "-dev") {
+		return fmt.Errorf("branch alias source '%s' must be a dev branch, e.g. dev-main", branch)
+	}
+
+	if !strings.HasSuffix(alias, "-dev") {
+		return fmt.Errorf("branch alias '%s' of '%s' must end with -dev, e.g. 2.x-dev", alias, branch)
+	}
+
+	normalized := version.NormalizeBranch(strings.TrimSuffix(alias, "-dev"))
+	if strings.HasPrefix(normalized, "dev-") {
+		return fmt.Errorf("branch alias '%s' of '%s' must be a numeric branch, e.g. 2.x-dev", alias, branch)
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package composer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBranchAliases(t *testing.T) {
+	config, _ := NewConfigFromData([]byte(`{
+		"version": "1.0.0",
+		"extra": {
+			"branch-alias": {
+				"dev-main": "2.x-dev",
+				"dev-next": "3.0.x-dev",
+				"dev-feature": "feature-dev",
+				"main": "2.x-dev",
+				"dev-old": "1.x"
+			}
+		}
+	}`), "composer.json")
+
+	expected := map[string]string{
+		"dev-main": "2.x-dev",
+		"dev-next": "3.0.x-dev",
+	}
+	if aliases := config.BranchAliases(); !reflect.DeepEqual(aliases, expected) {
+		t.Errorf("mismatch branch aliases:\nwant: %v\nhave: %v", expected, aliases)
+	}
+
+	errs := CheckBranchAliases(config)
+	var pointers []string
+	for _, err := range errs {
+		pointers = append(pointers, err.Pointer)
+	}
+
+	expectedPointers := []string{
+		"/extra/branch-alias/dev-feature",
+		"/extra/branch-alias/dev-old",
+		"/extra/branch-alias/main",
+	}
+	if !reflect.DeepEqual(pointers, expectedPointers) {
+		t.Errorf("mismatch pointers:\nwant: %v\nhave: %v", expectedPointers, pointers)
+	}
+}
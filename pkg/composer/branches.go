@@ -0,0 +1,42 @@
+package composer
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// CodeInvalidNonFeatureBranch is the code of the errors reported
+// for the patterns of the non-feature-branches field.
+const CodeInvalidNonFeatureBranch = "invalid-non-feature-branch"
+
+// IsNonFeatureBranch reports whether the branch matches one of the
+// non-feature-branches patterns, that is, it is a maintenance branch
+// whose version is guessed from the branch itself rather than from
+// the closest numeric branch.
+//
+// As in composer, the patterns are regular expressions
+// matched against the whole branch name.
+// Invalid patterns are ignored.
+func (c *Config) IsNonFeatureBranch(branch string) bool {
+	for _, pattern := range c.NonFeatureBranches {
+		re, err := compileBranchPattern(pattern)
+		if err == nil && re.MatchString(branch) {
+			return true
+		}
+	}
+	return false
+}
+
+// compileBranchPattern compiles the non-feature-branches pattern,
+// anchored as composer's VersionGuesser does.
+func compileBranchPattern(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, fmt.Errorf("non-feature-branches pattern is empty")
+	}
+
+	re, err := regexp.Compile("^(" + pattern + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("non-feature-branches pattern '%s' is invalid: %v", pattern, err)
+	}
+	return re, nil
+}
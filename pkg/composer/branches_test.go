@@ -0,0 +1,31 @@
+package composer
+
+import (
+	"testing"
+)
+
+func TestNonFeatureBranches(t *testing.T) {
+	config, errs := NewConfigFromData([]byte(`{
+		"version": "1.0.0",
+		"non-feature-branches": ["latest-.*", "stable", "broken-(", ""]
+	}`), "composer.json")
+
+	if errs.Len() != 2 || errs.Errors[0].Pointer != "/non-feature-branches/2" || errs.Errors[1].Pointer != "/non-feature-branches/3" {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+
+	tests := []struct {
+		branch   string
+		expected bool
+	}{
+		{branch: "latest-2022", expected: true},
+		{branch: "stable", expected: true},
+		{branch: "stable-2", expected: false},
+		{branch: "feature/latest-ui", expected: false},
+	}
+	for _, tt := range tests {
+		if actual := config.IsNonFeatureBranch(tt.branch); actual != tt.expected {
+			t.Errorf("IsNonFeatureBranch(%q) = %v, want %v", tt.branch, actual, tt.expected)
+		}
+	}
+}
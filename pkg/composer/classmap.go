@@ -0,0 +1,369 @@
+package composer
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// classmapExtensions are the extensions of the files
+// scanned for classes, as in composer.
+var classmapExtensions = map[string]bool{
+	".php": true,
+	".inc": true,
+	".hh":  true,
+}
+
+// BuildClassmap scans the files and dirs of the classmap field for
+// class, interface, trait and enum declarations and returns a map
+// of fully qualified class names to the paths of the files, relative
+// to the root dir. The files matching exclude-from-classmap are skipped.
+//
+// As in composer, if a class is declared in several files,
+// the first file found wins.
+func (a *Autoload) BuildClassmap(rootDir string) (map[string]string, error) {
+	return buildClassmap(context.Background(), "classmap", rootDir, a.Classmap, a.ExcludeFromClassmap, nil)
+}
+
+// BuildClassmapContext is like BuildClassmap, but the scan stops
+// with the error of the context when it is cancelled, and the
+// progress is recorded in the checkpoint, if it is not nil.
+//
+// Entries of the classmap field already completed in the checkpoint
+// are not scanned again, so an interrupted scan can be resumed by
+// passing the same checkpoint, for example, saved as JSON.
+//
+// The progress is reported to the ProgressReporter of the context
+// as the "classmap" step, the items are the entries of the field.
+func (a *Autoload) BuildClassmapContext(ctx context.Context, rootDir string, checkpoint *ClassmapCheckpoint) (map[string]string, error) {
+	return buildClassmap(ctx, "classmap", rootDir, a.Classmap, a.ExcludeFromClassmap, checkpoint)
+}
+
+// ClassmapCheckpoint is the progress of a classmap scan,
+// see Autoload.BuildClassmapContext.
+//
+// A checkpoint is only valid for the config it was
+// created for and must not be shared between configs.
+type ClassmapCheckpoint struct {
+	// Completed maps the scanned entries of the classmap field
+	// to the classes found in them and the paths of their files.
+	Completed map[string]map[string]string `json:"completed"`
+}
+
+// IsCompleted reports whether the entry of the classmap field is scanned.
+func (cp *ClassmapCheckpoint) IsCompleted(entry string) bool {
+	if cp == nil {
+		return false
+	}
+	_, ok := cp.Completed[entry]
+	return ok
+}
+
+// IsExcludedFromClassmap reports whether the file with the passed path,
+// relative to the config root, matches one of the exclude-from-classmap
+// patterns.
+//
+// As in composer, a pattern matches the path itself and everything
+// inside it, an asterisk matches any characters except a slash,
+// and a double asterisk matches any characters.
+func (a *Autoload) IsExcludedFromClassmap(path string) bool {
+	return isExcludedFromClassmap(path, a.ExcludeFromClassmap)
+}
+
+func isExcludedFromClassmap(path string, patterns []string) bool {
+	path = strings.TrimPrefix(filepath.ToSlash(path), "./")
+	for _, pattern := range patterns {
+		re := classmapExcludeRegexp(pattern)
+		if re != nil && re.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// multipleSlashesRegexp matches the repeated slashes in the paths.
+var multipleSlashesRegexp = regexp.MustCompile(`/+`)
+
+// classmapExcludeRegexp converts the exclude-from-classmap pattern
+// into a regexp as composer's AutoloadGenerator does.
+func classmapExcludeRegexp(pattern string) *regexp.Regexp {
+	pattern = strings.Trim(strings.ReplaceAll(pattern, `\`, "/"), "/")
+	pattern = strings.TrimPrefix(pattern, "./")
+	if pattern == "" {
+		return nil
+	}
+
+	quoted := regexp.QuoteMeta(multipleSlashesRegexp.ReplaceAllString(pattern, "/"))
+	quoted = strings.NewReplacer(`\*\*`, ".+?", `\*`, "[^/]+?").Replace(quoted)
+
+	re, err := regexp.Compile("^" + quoted + "($|/)")
+	if err != nil {
+		return nil
+	}
+	return re
+}
+
+func buildClassmap(ctx context.Context, step string, rootDir string, entries []string, excludes []string, checkpoint *ClassmapCheckpoint) (map[string]string, error) {
+	classmap := map[string]string{}
+	reportProgress(ctx, step, 0, len(entries))
+
+	for i, entry := range entries {
+		var classes map[string]string
+		if checkpoint.IsCompleted(entry) {
+			classes = checkpoint.Completed[entry]
+		} else {
+			var err error
+			classes, err = scanClassmapEntry(ctx, rootDir, entry, excludes)
+			if err != nil {
+				return nil, err
+			}
+
+			if checkpoint != nil {
+				if checkpoint.Completed == nil {
+					checkpoint.Completed = map[string]map[string]string{}
+				}
+				checkpoint.Completed[entry] = classes
+			}
+		}
+
+		for class, path := range classes {
+			if _, ok := classmap[class]; !ok {
+				classmap[class] = path
+			}
+		}
+		reportProgress(ctx, step, i+1, len(entries))
+	}
+
+	return classmap, nil
+}
+
+// scanClassmapEntry returns the classes declared in the files
+// of a single entry of the classmap field.
+func scanClassmapEntry(ctx context.Context, rootDir string, entry string, excludes []string) (map[string]string, error) {
+	classes := map[string]string{}
+	entryPath := filepath.Join(rootDir, filepath.FromSlash(entry))
+
+	err := filepath.Walk(entryPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		// Files listed explicitly are scanned whatever the extension.
+		if path != entryPath && !classmapExtensions[filepath.Ext(path)] {
+			return nil
+		}
+
+		rel, err := filepath.Rel(rootDir, path)
+		if err != nil {
+			return err
+		}
+
+		if isExcludedFromClassmap(rel, excludes) {
+			return nil
+		}
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		for _, class := range FindClasses(string(data)) {
+			if _, ok := classes[class]; !ok {
+				classes[class] = filepath.ToSlash(rel)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return classes, nil
+}
+
+// Classmap returns the classmap built from the autoload and
+// autoload-dev classmap fields, see Autoload.BuildClassmap.
+//
+// As in composer, the exclude-from-classmap patterns
+// of both sections apply to both of them, and the
+// target-dir is handled as in RootAutoloadPath.
+func (c *Config) Classmap() (map[string]string, error) {
+	return c.ClassmapContext(context.Background(), nil)
+}
+
+// ClassmapContext is like Classmap, but the scan can be cancelled
+// and resumed, see Autoload.BuildClassmapContext. The autoload and
+// autoload-dev fields are reported as the "classmap" and
+// "classmap-dev" steps.
+func (c *Config) ClassmapContext(ctx context.Context, checkpoint *ClassmapCheckpoint) (map[string]string, error) {
+	var excludes []string
+	excludes = append(excludes, c.Autoload.ExcludeFromClassmap...)
+	excludes = append(excludes, c.AutoloadDev.ExcludeFromClassmap...)
+
+	classmap, err := buildClassmap(ctx, "classmap", c.RootDir, c.rootAutoloadPaths(c.Autoload.Classmap), excludes, checkpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	dev, err := buildClassmap(ctx, "classmap-dev", c.RootDir, c.rootAutoloadPaths(c.AutoloadDev.Classmap), excludes, checkpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	for class, path := range dev {
+		if _, ok := classmap[class]; !ok {
+			classmap[class] = path
+		}
+	}
+	return classmap, nil
+}
+
+// declarationRegexp matches the declarations of classes and namespaces
+// in the PHP code without comments and strings, as composer's
+// PhpFileParser does.
+var declarationRegexp = regexp.MustCompile(`(?i)\b(class|interface|trait|enum)\s+([a-zA-Z_\x7f-\xff][a-zA-Z0-9_\x7f-\xff]*)` +
+	`|\b(namespace)(\s+[a-zA-Z_\x7f-\xff][a-zA-Z0-9_\x7f-\xff]*(?:\s*\\\s*[a-zA-Z_\x7f-\xff][a-zA-Z0-9_\x7f-\xff]*)*)?\s*[{;]`)
+
+// FindClasses returns the fully qualified names of the classes,
+// interfaces, traits and enums declared in the PHP code.
+func FindClasses(code string) []string {
+	code = stripPhpCode(code)
+
+	var classes []string
+	var namespace string
+
+	for _, m := range declarationRegexp.FindAllStringSubmatchIndex(code, -1) {
+		// Skip $class, ::class and ->class.
+		if m[0] > 0 && strings.ContainsRune("$:>", rune(code[m[0]-1])) {
+			continue
+		}
+
+		if m[6] >= 0 {
+			namespace = ""
+			if m[8] >= 0 {
+				namespace = strings.Join(strings.Fields(code[m[8]:m[9]]), "") + `\`
+			}
+			continue
+		}
+
+		name := code[m[4]:m[5]]
+
+		// Anonymous classes, for example, new class extends Foo {}.
+		if strings.EqualFold(name, "extends") || strings.EqualFold(name, "implements") {
+			continue
+		}
+
+		classes = append(classes, namespace+code[m[4]:m[5]])
+	}
+
+	return classes
+}
+
+// stripPhpCode replaces the comments, strings, heredocs and inline
+// HTML of the PHP code with spaces, so that only the code remains.
+func stripPhpCode(code string) string {
+	var b strings.Builder
+	b.Grow(len(code))
+
+	inPhp := false
+	for i := 0; i < len(code); {
+		if !inPhp {
+			if strings.HasPrefix(code[i:], "<?") {
+				inPhp = true
+				i += 2
+				if strings.HasPrefix(strings.ToLower(code[i:]), "php") {
+					i += 3
+				}
+				b.WriteByte(' ')
+				continue
+			}
+			i++
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(code[i:], "?>"):
+			inPhp = false
+			i += 2
+			b.WriteString(";")
+		case strings.HasPrefix(code[i:], "//") || code[i] == '#' && !strings.HasPrefix(code[i:], "#["):
+			for i < len(code) && code[i] != '\n' && !strings.HasPrefix(code[i:], "?>") {
+				i++
+			}
+		case strings.HasPrefix(code[i:], "/*"):
+			end := strings.Index(code[i+2:], "*/")
+			if end < 0 {
+				return b.String()
+			}
+			i += end + 4
+			b.WriteByte(' ')
+		case code[i] == '\'' || code[i] == '"':
+			quote := code[i]
+			i++
+			for i < len(code) && code[i] != quote {
+				if code[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			i++
+			b.WriteString("null")
+		case strings.HasPrefix(code[i:], "<<<"):
+			i = skipHeredoc(code, i)
+			b.WriteString("null")
+		default:
+			b.WriteByte(code[i])
+			i++
+		}
+	}
+
+	return b.String()
+}
+
+// heredocRegexp matches the start of a heredoc or a nowdoc.
+var heredocRegexp = regexp.MustCompile(`^<<<[ \t]*["']?([a-zA-Z_\x7f-\xff][a-zA-Z0-9_\x7f-\xff]*)["']?\r?\n`)
+
+// skipHeredoc returns the position after the heredoc started at i.
+func skipHeredoc(code string, i int) int {
+	m := heredocRegexp.FindStringSubmatch(code[i:])
+	if m == nil {
+		return i + 3
+	}
+
+	label := m[1]
+	pos := i + len(m[0])
+	for pos < len(code) {
+		lineEnd := strings.IndexByte(code[pos:], '\n')
+		line := code[pos:]
+		if lineEnd >= 0 {
+			line = code[pos : pos+lineEnd]
+		}
+
+		trimmed := strings.TrimLeft(line, " \t")
+		if strings.HasPrefix(trimmed, label) {
+			rest := trimmed[len(label):]
+			if rest == "" || !isIdentChar(rest[0]) {
+				return pos + (len(line) - len(trimmed)) + len(label)
+			}
+		}
+
+		if lineEnd < 0 {
+			break
+		}
+		pos += lineEnd + 1
+	}
+	return len(code)
+}
+
+func isIdentChar(ch byte) bool {
+	return ch == '_' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9' || ch >= 0x7f
+}
@@ -0,0 +1,186 @@
+package composer
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFindClasses(t *testing.T) {
+	code := `<html><?php class NotInPhp {} ?>
+<?php
+namespace App\Models;
+
+// class CommentedOut {}
+/* interface AlsoCommented {} */
+$str = "class InString {}";
+$doc = <<<EOT
+trait InHeredoc {}
+EOT;
+
+#[Attribute]
+final class User extends Model
+{
+    public function name() { return static::class; }
+    public function anonymous() { return new class extends Base {}; }
+}
+
+interface HasName {}
+trait Named {}
+enum Status: string {}
+`
+
+	expected := []string{
+		"NotInPhp",
+		`App\Models\User`,
+		`App\Models\HasName`,
+		`App\Models\Named`,
+		`App\Models\Status`,
+	}
+	if classes := FindClasses(code); !reflect.DeepEqual(classes, expected) {
+		t.Errorf("mismatch classes:\nwant: %v\nhave: %v", expected, classes)
+	}
+}
+
+func TestClassmap(t *testing.T) {
+	dir, err := ioutil.TempDir("", "composer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"src/Legacy/Foo.php":   "<?php class Legacy_Foo {}",
+		"src/Legacy/Bar.inc":   "<?php namespace Legacy; interface Bar {}",
+		"src/Legacy/notes.txt": "<?php class NotScanned {}",
+		"lib/functions.module": "<?php class ExplicitFile {}",
+		"tests/FooTest.php":    "<?php class FooTest {}",
+	}
+	for file, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config, _ := NewConfigFromData([]byte(`{
+		"version": "1.0.0",
+		"autoload": {"classmap": ["src/", "lib/functions.module"]},
+		"autoload-dev": {"classmap": ["tests/"]}
+	}`), filepath.Join(dir, "composer.json"))
+
+	classmap, err := config.Classmap()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"Legacy_Foo":   "src/Legacy/Foo.php",
+		`Legacy\Bar`:   "src/Legacy/Bar.inc",
+		"ExplicitFile": "lib/functions.module",
+		"FooTest":      "tests/FooTest.php",
+	}
+	if !reflect.DeepEqual(classmap, expected) {
+		t.Errorf("mismatch classmap:\nwant: %v\nhave: %v", expected, classmap)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	checkpoint := &ClassmapCheckpoint{}
+	if _, err := config.ClassmapContext(ctx, checkpoint); err != context.Canceled {
+		t.Errorf("expected the scan to be cancelled, got %v", err)
+	}
+	if len(checkpoint.Completed) != 0 {
+		t.Errorf("expected no completed entries, got %v", checkpoint.Completed)
+	}
+
+	// Completed entries are taken from the checkpoint without scanning.
+	checkpoint.Completed = map[string]map[string]string{
+		"src/": {"FromCheckpoint": "src/FromCheckpoint.php"},
+	}
+	classmap, err = config.ClassmapContext(context.Background(), checkpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := classmap["FromCheckpoint"]; !ok || classmap["Legacy_Foo"] != "" {
+		t.Errorf("expected src/ to be taken from the checkpoint, got %v", classmap)
+	}
+	if !checkpoint.IsCompleted("tests/") || !checkpoint.IsCompleted("lib/functions.module") {
+		t.Errorf("expected the scanned entries to be recorded, got %v", checkpoint.Completed)
+	}
+
+	var progress []string
+	ctx = ContextWithProgress(context.Background(), ProgressFunc(func(step string, done, total int) {
+		progress = append(progress, fmt.Sprintf("%s %d/%d", step, done, total))
+	}))
+	if _, err := config.ClassmapContext(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+	expectedProgress := []string{"classmap 0/2", "classmap 1/2", "classmap 2/2", "classmap-dev 0/1", "classmap-dev 1/1"}
+	if !reflect.DeepEqual(progress, expectedProgress) {
+		t.Errorf("unexpected progress: %v", progress)
+	}
+}
+
+func TestExcludeFromClassmap(t *testing.T) {
+	autoload := Autoload{
+		ExcludeFromClassmap: []string{"/Tests/", "/test/", "src/**/Fixtures/", "lib/*/Stub.php"},
+	}
+
+	tests := []struct {
+		path     string
+		excluded bool
+	}{
+		{path: "Tests/FooTest.php", excluded: true},
+		{path: "test", excluded: true},
+		{path: "testing/Foo.php", excluded: false},
+		{path: "src/Tests/Foo.php", excluded: false},
+		{path: "src/Bundle/Resources/Fixtures/Foo.php", excluded: true},
+		{path: "src/Fixtures/Foo.php", excluded: false},
+		{path: "lib/Http/Stub.php", excluded: true},
+		{path: "lib/Http/Client/Stub.php", excluded: false},
+	}
+
+	for _, tt := range tests {
+		if excluded := autoload.IsExcludedFromClassmap(tt.path); excluded != tt.excluded {
+			t.Errorf("IsExcludedFromClassmap(%q) = %v, want %v", tt.path, excluded, tt.excluded)
+		}
+	}
+
+	dir, err := ioutil.TempDir("", "composer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, file := range []string{"src/Foo.php", "src/Tests/FooTest.php"} {
+		path := filepath.Join(dir, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte("<?php class "+strings.TrimSuffix(filepath.Base(file), ".php")+" {}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config, _ := NewConfigFromData([]byte(`{
+		"version": "1.0.0",
+		"autoload": {"classmap": ["src/"], "exclude-from-classmap": ["src/Tests/"]}
+	}`), filepath.Join(dir, "composer.json"))
+
+	classmap, err := config.Classmap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(classmap, map[string]string{"Foo": "src/Foo.php"}) {
+		t.Errorf("unexpected classmap: %v", classmap)
+	}
+}
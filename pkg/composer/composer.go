@@ -7,9 +7,12 @@ package composer
 
 import (
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/i582/go-composer.json/internal/version"
 )
@@ -59,12 +62,180 @@ type Config struct {
 	// Parsed version.
 	Version *version.Version
 
+	// Release date of the version.
+	//
+	// Must be in YYYY-MM-DD or YYYY-MM-DD HH:MM:SS format.
+	//
+	// Optional.
+	RawTime string `json:"time"`
+	// Parsed release date, zero if the time is not set or invalid.
+	Time time.Time `json:"-"`
+
+	// The license of the package. This can be either a string or an array of strings.
+	//
+	// The recommended notation for the most common licenses is (alphabetical):
+	//   Apache-2.0
+	//   BSD-2-Clause
+	//   BSD-3-Clause
+	//   BSD-4-Clause
+	//   GPL-2.0-only / GPL-2.0-or-later
+	//   GPL-3.0-only / GPL-3.0-or-later
+	//   LGPL-2.1-only / LGPL-2.1-or-later
+	//   LGPL-3.0-only / LGPL-3.0-or-later
+	//   MIT
+	//
+	// Optional, but it is highly recommended to supply this.
+	// For closed-source software, you may use "proprietary" as the license identifier.
+	License License `json:"license"`
+
+	// The authors of the package. This is an array of objects.
+	//
+	// Optional, but highly recommended.
+	Authors []Author `json:"authors"`
+
+	// A URL to the website of the project.
+	//
+	// Optional.
+	Homepage string `json:"homepage"`
+
+	// A relative path to the readme document. Defaults to README.md.
+	//
+	// Optional.
+	Readme string `json:"readme"`
+
+	// Various information to get support about the project.
+	//
+	// Optional.
+	Support Support `json:"support"`
+
+	// A set of files that should be treated as binaries and made available
+	// into the bin-dir (from config).
+	//
+	// Optional.
+	Bin Binaries `json:"bin"`
+
+	// Indicates whether this package has been abandoned.
+	//
+	// It can be boolean or a package name/URL pointing to a recommended
+	// alternative, see Config.IsAbandoned and Config.ReplacementPackage.
+	//
+	// Optional.
+	Abandoned BoolOrString `json:"abandoned"`
+
+	// A list of URLs to provide funding to the package authors for maintenance
+	// and development of new functionality.
+	//
+	// Optional.
+	Funding []FundingEntry `json:"funding"`
+
+	// An array of keywords that the package is related to.
+	// These can be used for searching and filtering.
+	//
+	// Examples:
+	//   logging
+	//   events
+	//   database
+	//   redis
+	//   templating
+	//
+	// Optional.
+	Keywords []string `json:"keywords"`
+
 	Type        string            `json:"type"`
 	Require     map[string]string `json:"require"`
 	RequireDev  map[string]string `json:"require-dev"`
 	Reps        []*ConfigRepo     `json:"repositories"`
 	Autoload    Autoload          `json:"autoload"`
 	AutoloadDev Autoload          `json:"autoload-dev"`
+	Settings    ComposerConfig    `json:"config"`
+
+	// Map of packages that conflict with this version of this package.
+	// They will not be allowed to be installed together with your package.
+	Conflict map[string]string `json:"conflict"`
+
+	// Map of packages that are replaced by this package.
+	// This allows you to fork a package, publish it under a different name
+	// with its own version numbers, while packages requiring the original
+	// package continue to work with your fork because it replaces the original package.
+	Replace map[string]string `json:"replace"`
+
+	// Map of packages that are provided by this package. This is mostly useful for
+	// implementations of common interfaces. A package could depend on some virtual
+	// package e.g. psr/logger-implementation, any library that implements this logger
+	// interface would list it in provide. Platform packages, such as ext-mbstring,
+	// can also be provided, for example, by polyfills.
+	Provide map[string]string `json:"provide"`
+
+	// Suggested packages that can enhance or work well with this package.
+	// These are informational and are displayed after the package is installed,
+	// to give your users a hint that they could add more packages, even though
+	// they are not strictly required.
+	//
+	// The format is like package links above, except that the values are free text
+	// and not version constraints.
+	//
+	// Example:
+	//   "monolog/monolog": "Allows more advanced logging of the application flow",
+	//   "ext-xml": "Needed to support XML format in class Foo"
+	Suggest map[string]string `json:"suggest"`
+
+	// This defines the default behavior for filtering packages by stability.
+	// This defaults to stable, so if you rely on a dev package, you should specify
+	// it in your file to avoid surprises.
+	//
+	// Available options (in order of stability) are dev, alpha, beta, RC, and stable.
+	MinimumStability string `json:"minimum-stability"`
+
+	// When this is enabled, Composer will prefer more stable packages over unstable
+	// ones when finding compatible stable packages is possible.
+	PreferStable bool `json:"prefer-stable"`
+
+	// Scripts are PHP callbacks or command-line executables that are executed
+	// at various points of the Composer execution process, or custom commands
+	// run by "composer run-script".
+	//
+	// Each event maps to a single command or to an array of commands,
+	// see Scripts.
+	Scripts Scripts `json:"scripts"`
+
+	// Custom descriptions of the custom commands of the scripts section,
+	// shown by "composer list".
+	//
+	// Example:
+	//   "test": "Run all tests!"
+	ScriptsDescriptions map[string]string `json:"scripts-descriptions"`
+
+	// Aliases of the custom commands of the scripts section.
+	//
+	// Example:
+	//   "phpstan": ["stan", "analyze"]
+	ScriptsAliases map[string][]string `json:"scripts-aliases"`
+
+	// Arbitrary extra data for consumption by scripts, plugins and frameworks,
+	// the values are kept as is, see Config.ExtraInto.
+	Extra map[string]json.RawMessage `json:"extra"`
+
+	// Defines the installation target, deprecated since PSR-4.
+	//
+	// In case the package root is below the namespace declaration
+	// (PSR-0 packages), the files are installed into this dir of the
+	// package dir, see Config.InstallPath. It cannot be used together
+	// with autoload.psr-4.
+	//
+	// Example:
+	//   "target-dir": "Symfony/Component/Yaml"
+	TargetDir string `json:"target-dir"`
+
+	// A set of options for creating package archives, see Archive.
+	Archive Archive `json:"archive"`
+
+	// A list of regex patterns of branch names that are non-numeric
+	// (e.g. "latest" or something), that will NOT be handled as feature
+	// branches, see Config.IsNonFeatureBranch.
+	//
+	// Example:
+	//   "non-feature-branches": ["latest-.*"]
+	NonFeatureBranches []string `json:"non-feature-branches"`
 
 	// Path to the config.
 	Path string
@@ -73,6 +244,108 @@ type Config struct {
 	// Checks is a custom checks for config,
 	// see Config.AddCheck, Config.CheckConfig.
 	Checks []func(*Config) *ConfigError
+	// Providers is a custom check providers for config,
+	// see Config.AddCheckProvider, Config.CheckConfig.
+	Providers []CheckProvider `json:"-"`
+	// ContextChecks is a custom checks that receive a CheckContext,
+	// see Config.RegisterCheck, Config.CheckConfig.
+	ContextChecks []ContextCheck `json:"-"`
+	// ReadOnly is true if the config was loaded in
+	// read-only mode, see LoadOptions.ReadOnly.
+	ReadOnly bool `json:"-"`
+	// Raw is the content of composer.json as it was loaded.
+	Raw []byte `json:"-"`
+	// Suppressions is a list of errors suppressed in the config
+	// itself, see Suppression.
+	Suppressions []Suppression `json:"-"`
+}
+
+// timeLayouts are the formats of the time field.
+var timeLayouts = []string{
+	"2006-01-02",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	time.RFC3339,
+}
+
+// parseTime parses the time field in one of the timeLayouts formats.
+func parseTime(raw string) (time.Time, error) {
+	for _, layout := range timeLayouts {
+		t, err := time.Parse(layout, raw)
+		if err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("time '%s' must be in the format YYYY-MM-DD or YYYY-MM-DD HH:MM:SS", raw)
+}
+
+// ReadmePath returns the path to the readme document relative
+// to the config root, README.md if the readme field is not set.
+func (c *Config) ReadmePath() string {
+	if c.Readme == "" {
+		return "README.md"
+	}
+	return c.Readme
+}
+
+// IsAbandoned returns true if the package is marked as abandoned.
+func (c *Config) IsAbandoned() bool {
+	return c.Abandoned.Bool || c.Abandoned.String != ""
+}
+
+// ReplacementPackage returns the package recommended instead of
+// the abandoned one, an empty string if there is none.
+func (c *Config) ReplacementPackage() string {
+	return c.Abandoned.String
+}
+
+// Author is a structure for storing one of the authors of the package.
+//
+// All fields are optional.
+type Author struct {
+	// The author's name. Usually their real name.
+	Name string `json:"name"`
+	// The author's email address.
+	Email string `json:"email"`
+	// URL to the author's website.
+	Homepage string `json:"homepage"`
+	// The author's role in the project (e.g. developer or translator).
+	Role string `json:"role"`
+}
+
+// Support is a structure for storing the information
+// to get support about the project.
+type Support struct {
+	// Email address for support.
+	Email string `json:"email"`
+	// URL to the issue tracker.
+	Issues string `json:"issues"`
+	// URL to the forum.
+	Forum string `json:"forum"`
+	// URL to the wiki.
+	Wiki string `json:"wiki"`
+	// IRC channel for support, as irc://server/channel.
+	Irc string `json:"irc"`
+	// URL to browse or download the sources.
+	Source string `json:"source"`
+	// URL to the documentation.
+	Docs string `json:"docs"`
+	// URL to the RSS feed.
+	Rss string `json:"rss"`
+	// URL to the chat channel.
+	Chat string `json:"chat"`
+	// URL to the vulnerability disclosure policy (VDP).
+	Security string `json:"security"`
+}
+
+// FundingEntry is a structure for storing one of the ways
+// to fund the package authors.
+type FundingEntry struct {
+	// The type of funding, or the platform through which funding
+	// can be provided, e.g. patreon, opencollective, tidelift or github.
+	Type string `json:"type"`
+	// URL to a website with details, and a way to fund the package.
+	Url string `json:"url"`
 }
 
 // Autoload structure stores a mapping to namespaces
@@ -80,16 +353,25 @@ type Config struct {
 //
 // See root.handleNamespace function
 type Autoload struct {
-	Psr4  map[string]string `json:"psr-4"`
-	Files []string          `json:"files"`
+	Psr4  Psr4     `json:"psr-4"`
+	Files []string `json:"files"`
+	// Classmap is a list of files and dirs scanned for classes,
+	// see Autoload.BuildClassmap.
+	Classmap []string `json:"classmap"`
+	// ExcludeFromClassmap is a list of patterns of the paths excluded
+	// from the classmap, see Autoload.IsExcludedFromClassmap.
+	ExcludeFromClassmap []string `json:"exclude-from-classmap"`
 }
 
-// Psr4PathForNamespace for the passed namespace looks for the path
+// Psr4PathForNamespace for the passed namespace looks for the paths
 // in the current autoload psr-4 field.
 //
 // The search is not performed verbatim, it is enough
 // that the namespace is a prefix of one of the psr-4 map keys.
-func (a *Autoload) Psr4PathForNamespace(name string) (string, bool) {
+//
+// A namespace can be mapped to several paths, composer looks
+// for the class in all of them, so all paths are returned.
+func (a *Autoload) Psr4PathForNamespace(name string) ([]string, bool) {
 	// Since names in psr-4 always end with a slash, we need to add a
 	// slash to the namespace name to properly handle the case when
 	// the namespace name is equal to the name in psr-4.
@@ -107,32 +389,35 @@ func (a *Autoload) Psr4PathForNamespace(name string) (string, bool) {
 	//
 	// So, we have to choose the largest prefix found in order to work correctly.
 	var psrNameForFound string
-	var foundPath string
+	var foundPaths []string
+	var found bool
 
-	for psrName, psrPath := range a.Psr4 {
+	for psrName, psrPaths := range a.Psr4 {
 		if strings.HasPrefix(name, psrName) {
-			if len(psrName) > len(psrNameForFound) {
+			if !found || len(psrName) > len(psrNameForFound) {
 				psrNameForFound = psrName
-				foundPath = psrPath
+				foundPaths = psrPaths
+				found = true
 			}
 		}
 	}
 
-	if foundPath == "" {
-		return "", false
+	if !found {
+		return nil, false
 	}
 
-	return foundPath, true
+	return foundPaths, true
 }
 
-// Psr4PathForNamespace for the passed namespace looks for the path
+// Psr4PathForNamespace for the passed namespace looks for the paths
 // in the autoload.psr-4 and autoload-dev.psr-4 fields.
 //
-// Returns the found path starting with the folder where
-// the microservice or package is located.
+// Returns the found paths starting with the folder where
+// the microservice or package is located. The target-dir
+// is handled as in RootAutoloadPath.
 //
 // See Autoload.Psr4PathForNamespace
-func (c *Config) Psr4PathForNamespace(name string) (string, bool) {
+func (c *Config) Psr4PathForNamespace(name string) ([]string, bool) {
 	// We need to add a folder to the resulting path to
 	// avoid triggers when there is a folder with the
 	// same name in the path.
@@ -141,17 +426,19 @@ func (c *Config) Psr4PathForNamespace(name string) (string, bool) {
 	//   path:       core/tests/some/src/
 	dir := filepath.Base(c.RootDir)
 
-	path, contains := c.Autoload.Psr4PathForNamespace(name)
-	if contains {
-		return dir + "/" + path, true
+	paths, contains := c.Autoload.Psr4PathForNamespace(name)
+	if !contains {
+		paths, contains = c.AutoloadDev.Psr4PathForNamespace(name)
 	}
-
-	path, containsInDev := c.AutoloadDev.Psr4PathForNamespace(name)
-	if containsInDev {
-		return dir + "/" + path, true
+	if !contains {
+		return nil, false
 	}
 
-	return "", false
+	res := make([]string, 0, len(paths))
+	for _, path := range c.rootAutoloadPaths(paths) {
+		res = append(res, dir+"/"+path)
+	}
+	return res, true
 }
 
 // ConfigRepo is a structure for storing dependencies
@@ -160,12 +447,71 @@ type ConfigRepo struct {
 	Type     string `json:"type"`
 	Url      string `json:"url"`
 	Resolved bool
+
+	// Name is the optional name of the repository,
+	// used to refer to it, for example, in composer config.
+	Name string `json:"name"`
+	// Options are the options of a composer repository,
+	// such as the ssl settings and the http headers.
+	Options *RepoOptions `json:"options"`
+	// AllowSslDowngrade allows a composer repository with
+	// an https url to serve the metadata over http.
+	AllowSslDowngrade bool `json:"allow_ssl_downgrade"`
+
+	// readOnly is set for repositories of read-only configs,
+	// see LoadOptions.ReadOnly.
+	readOnly bool
+}
+
+// LoadOptions describes how the config is loaded.
+type LoadOptions struct {
+	// Lenient allows to continue loading past critical errors
+	// that do not prevent reading the rest of the config,
+	// for example, a field with a value of the wrong type.
+	//
+	// Such errors are still reported as critical, but the
+	// config contains all the fields that could be read.
+	// Errors in the json syntax always stop the loading.
+	Lenient bool
+
+	// ReadOnly guarantees that no API of the config has side effects:
+	// ConfigRepo.ResolveUrl returns a resolved copy instead of modifying
+	// the repository, and external check providers are not run, since
+	// they may modify the filesystem.
+	//
+	// Use it when the scanned repositories must not be modified.
+	ReadOnly bool
+
+	// Unmarshal is the JSON decoder used to load the config,
+	// by default json.Unmarshal from encoding/json.
+	//
+	// Any decoder compatible with encoding/json can be used, for example,
+	// to evaluate a faster engine in bulk scanning. The decoder must respect
+	// the json tags and the json.Unmarshaler implementations of the fields.
+	// In lenient mode only *json.UnmarshalTypeError errors are recoverable.
+	Unmarshal func(data []byte, v interface{}) error
+
+	// SchemaVersion is the composer version the config is written for,
+	// for example, "2.2". Empty means the latest version.
+	//
+	// Keys added to the schema in later versions of composer, such as
+	// config.allow-plugins for 2.2, are reported, since the older composer
+	// silently ignores them. Under the latest schema all keys are known.
+	SchemaVersion string
 }
 
 // NewConfigFromFile returns new config from file.
 //
 // If the file does not exist or contains invalid json an error will be returned.
 func NewConfigFromFile(path string) (*Config, *ConfigErrors) {
+	return NewConfigFromFileWithOptions(path, LoadOptions{})
+}
+
+// NewConfigFromFileWithOptions returns new config from file
+// loaded with the passed options.
+//
+// See NewConfigFromFile
+func NewConfigFromFileWithOptions(path string, opts LoadOptions) (*Config, *ConfigErrors) {
 	data, err := ioutil.ReadFile(path)
 	if err != nil {
 		return &Config{}, NewConfigErrors(&ConfigError{
@@ -173,38 +519,164 @@ func NewConfigFromFile(path string) (*Config, *ConfigErrors) {
 			Critical: true,
 		})
 	}
-	return NewConfigFromData(data, path)
+	return NewConfigFromDataWithOptions(data, path, opts)
 }
 
 // NewConfigFromData returns new config from data.
 //
 // If data contains invalid json an error will be returned.
 func NewConfigFromData(data []byte, configPath string) (*Config, *ConfigErrors) {
+	return NewConfigFromDataWithOptions(data, configPath, LoadOptions{})
+}
+
+// NewConfigFromDataWithOptions returns new config from data
+// loaded with the passed options.
+//
+// See NewConfigFromData
+func NewConfigFromDataWithOptions(data []byte, configPath string, opts LoadOptions) (*Config, *ConfigErrors) {
 	var config Config
-	err := json.Unmarshal(data, &config)
+	var configErrors = &ConfigErrors{Config: &config}
+
+	config.Raw = data
+	config.Settings = DefaultComposerConfig()
+
+	unmarshal := opts.Unmarshal
+	if unmarshal == nil {
+		unmarshal = json.Unmarshal
+	}
+
+	decodeErr := unmarshal(data, &config)
+	if decodeErr != nil {
+		_, isTypeError := decodeErr.(*json.UnmarshalTypeError)
+		if !opts.Lenient || !isTypeError {
+			// The misplaced keys often explain why the config cannot be read,
+			// for example, autoload.files given as a string.
+			errs := NewConfigErrors(decodeErrors(data, decodeErr, unmarshal)...)
+			errs.Errors = append(errs.Errors, checkKeys(data)...)
+			return &Config{}, errs
+		}
+	}
+
+	// The suppressions are read first so that they also apply
+	// to the type errors skipped in the lenient mode.
+	var err error
+	config.Suppressions, err = parseSuppressions(data, unmarshal)
 	if err != nil {
-		return &Config{}, NewConfigErrors(&ConfigError{
+		configErrors.Add(&ConfigError{
 			Msg:      err.Error(),
-			Critical: true,
+			Critical: false,
+			Code:     CodeInvalidSuppression,
+			Pointer:  "/extra/composer-check/ignore",
 		})
 	}
 
-	var configErrors = &ConfigErrors{Config: &config}
+	if decodeErr != nil {
+		for _, err := range decodeErrors(data, decodeErr, unmarshal) {
+			configErrors.Add(err)
+		}
+	}
+
+	for _, err := range checkKeys(data) {
+		configErrors.Add(err)
+	}
+
+	if opts.SchemaVersion != "" {
+		for _, err := range checkSchemaKeys(data, opts.SchemaVersion) {
+			configErrors.Add(err)
+		}
+	}
 
 	config.Version, err = version.NewVersion(config.RawVersion)
 	if err != nil {
 		configErrors.Add(&ConfigError{
 			Msg:      err.Error(),
 			Critical: false,
+			Code:     CodeInvalidVersion,
+			Pointer:  "/version",
+		})
+	}
+
+	if config.RawTime != "" {
+		config.Time, err = parseTime(config.RawTime)
+		if err != nil {
+			configErrors.Add(&ConfigError{
+				Msg:      err.Error(),
+				Critical: false,
+				Code:     CodeInvalidTime,
+				Pointer:  "/time",
+			})
+		}
+	}
+
+	if config.MinimumStability != "" {
+		if _, ok := version.ParseStability(config.MinimumStability); !ok {
+			configErrors.Add(&ConfigError{
+				Msg:      fmt.Sprintf("minimum-stability '%s' must be one of dev, alpha, beta, RC or stable", config.MinimumStability),
+				Critical: false,
+				Code:     CodeInvalidMinimumStability,
+				Pointer:  "/minimum-stability",
+			})
+		}
+	}
+
+	for i, license := range config.License {
+		if err := ValidateLicense(license); err != nil {
+			pointer := "/license"
+			if len(config.License) > 1 {
+				pointer += "/" + strconv.Itoa(i)
+			}
+
+			configErrors.Add(&ConfigError{
+				Msg:      err.Error(),
+				Critical: false,
+				Code:     CodeInvalidLicense,
+				Pointer:  pointer,
+			})
+		}
+	}
+
+	if config.TargetDir != "" && len(config.Autoload.Psr4) > 0 {
+		configErrors.Add(&ConfigError{
+			Msg:      "target-dir cannot be used together with autoload.psr-4, remove target-dir to upgrade to psr-4",
+			Critical: false,
+			Code:     CodeInvalidTargetDir,
+			Pointer:  "/target-dir",
+		})
+	}
+
+	if err := ValidateType(config.Type); err != nil {
+		configErrors.Add(&ConfigError{
+			Msg:      err.Error(),
+			Critical: false,
+			Code:     CodeInvalidType,
+			Pointer:  "/type",
 		})
 	}
 
+	for i, pattern := range config.NonFeatureBranches {
+		if _, err := compileBranchPattern(pattern); err != nil {
+			configErrors.Add(&ConfigError{
+				Msg:      err.Error(),
+				Critical: false,
+				Code:     CodeInvalidNonFeatureBranch,
+				Pointer:  "/non-feature-branches/" + strconv.Itoa(i),
+			})
+		}
+	}
+
 	absPath, _ := filepath.Abs(configPath)
 	root := filepath.Dir(absPath)
 
 	config.Path = absPath
 	config.RootDir = root
 
+	if opts.ReadOnly {
+		config.ReadOnly = true
+		for _, repo := range config.Reps {
+			repo.readOnly = true
+		}
+	}
+
 	if configErrors.Len() != 0 {
 		return &config, configErrors
 	}
@@ -217,17 +689,72 @@ func (c *Config) AddCheck(check func(*Config) *ConfigError) {
 	c.Checks = append(c.Checks, check)
 }
 
+// AddCheckProvider adds custom check provider for config.
+//
+// Unlike checks added with AddCheck, a provider can report
+// any number of errors, see ExternalCheckProvider.
+func (c *Config) AddCheckProvider(p CheckProvider) {
+	c.Providers = append(c.Providers, p)
+}
+
+// CheckOptions describes how the checks are run.
+type CheckOptions struct {
+	// MaxErrors is the maximum number of errors after which
+	// the remaining checks are not run. Zero means no limit.
+	MaxErrors int
+	// StopOnCritical stops the checks on the first critical error.
+	StopOnCritical bool
+}
+
 // CheckConfig checks the config against the rules.
 //
-// See Config.AddCheck
+// See Config.AddCheck, Config.AddCheckProvider
 func (c *Config) CheckConfig() *ConfigErrors {
+	return c.CheckConfigWithOptions(CheckOptions{})
+}
+
+// CheckConfigWithOptions checks the config against the rules
+// and stops when the limits from the options are reached.
+//
+// See Config.CheckConfig
+func (c *Config) CheckConfigWithOptions(opts CheckOptions) *ConfigErrors {
 	errors := &ConfigErrors{
 		Config: c,
 	}
 
+	checks := make([]func(*Config) []*ConfigError, 0, len(c.Checks)+len(c.Providers)+len(c.ContextChecks))
 	for _, check := range c.Checks {
-		if err := check(c); err != nil {
+		check := check
+		checks = append(checks, func(c *Config) []*ConfigError {
+			if err := check(c); err != nil {
+				return []*ConfigError{err}
+			}
+			return nil
+		})
+	}
+	for _, provider := range c.Providers {
+		checks = append(checks, provider.Check)
+	}
+
+	ctx := newCheckContext(c)
+	for _, check := range c.ContextChecks {
+		check := check
+		checks = append(checks, func(c *Config) []*ConfigError {
+			return check(ctx)
+		})
+	}
+
+loop:
+	for _, check := range checks {
+		for _, err := range check(c) {
 			errors.Add(err)
+
+			if opts.StopOnCritical && err.Critical {
+				break loop
+			}
+			if opts.MaxErrors > 0 && errors.Len() >= opts.MaxErrors {
+				break loop
+			}
 		}
 	}
 
@@ -239,23 +766,50 @@ func (c *Config) CheckConfig() *ConfigErrors {
 }
 
 // ResolveUrl resolves the path for the dependency relative to the passed path.
+//
+// Path repositories and VCS repositories on the local filesystem
+// are resolved, other repositories are returned as is.
+//
+// If the config was loaded in read-only mode, the repository
+// is not modified, and a resolved copy is returned instead.
 func (c *ConfigRepo) ResolveUrl(path string) *ConfigRepo {
 	if c.Resolved {
 		return c
 	}
 
-	// At the moment it is not clear how to handle non-local
-	// inclusions (for example via vcs).
-	if c.Type != "path" {
+	// Only local paths are resolved, remote VCS urls
+	// are left as is, see ConfigRepo.NormalizedUrl.
+	if !c.isLocal() {
 		return c
 	}
 
-	c.Url = filepath.Clean(filepath.Join(path, c.Url))
+	repo := c
+	if c.readOnly {
+		copied := *c
+		repo = &copied
+	}
+
+	repo.Url = c.ResolvedUrl(path)
+	repo.Resolved = true
+	return repo
+}
+
+// isLocal reports whether the repository is a path repository
+// or a VCS repository on the local filesystem.
+func (c *ConfigRepo) isLocal() bool {
+	return c.Kind() == RepoKindPath || c.Kind() == RepoKindVcs && isLocalRepoUrl(c.Url)
+}
+
+// ResolvedUrl returns the url of the dependency resolved relative
+// to the passed path without modifying the repository.
+func (c *ConfigRepo) ResolvedUrl(path string) string {
+	if c.Resolved || !c.isLocal() || filepath.IsAbs(c.Url) || strings.HasPrefix(c.Url, "file://") {
+		return c.Url
+	}
+
+	url := filepath.Clean(filepath.Join(path, c.Url))
 
 	// In order to correctly handle paths in unix-like systems and in windows,
 	// we need to bring all slashes to the form as in unix.
-	c.Url = filepath.ToSlash(c.Url)
-
-	c.Resolved = true
-	return c
+	return filepath.ToSlash(url)
 }
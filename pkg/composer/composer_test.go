@@ -0,0 +1,207 @@
+package composer
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCheckOptions(t *testing.T) {
+	config := &Config{}
+	for _, critical := range []bool{false, false, true, false} {
+		critical := critical
+		config.AddCheck(func(*Config) *ConfigError {
+			return &ConfigError{Msg: "error", Critical: critical}
+		})
+	}
+
+	tests := []struct {
+		Options  CheckOptions
+		Expected int
+	}{
+		{Options: CheckOptions{}, Expected: 4},
+		{Options: CheckOptions{MaxErrors: 2}, Expected: 2},
+		{Options: CheckOptions{StopOnCritical: true}, Expected: 3},
+		{Options: CheckOptions{MaxErrors: 1, StopOnCritical: true}, Expected: 1},
+	}
+
+	for _, test := range tests {
+		errs := config.CheckConfigWithOptions(test.Options)
+		if errs.Len() != test.Expected {
+			t.Errorf("%+v: expected %d errors, got %d", test.Options, test.Expected, errs.Len())
+		}
+	}
+}
+
+func TestAuthors(t *testing.T) {
+	config, _ := NewConfigFromData([]byte(`{
+		"authors": [
+			{"name": "Nils Adermann", "email": "naderman@naderman.de", "homepage": "https://www.naderman.de", "role": "Developer"},
+			{"name": "Jordi Boggiano"}
+		]
+	}`), "composer.json")
+
+	expected := []Author{
+		{Name: "Nils Adermann", Email: "naderman@naderman.de", Homepage: "https://www.naderman.de", Role: "Developer"},
+		{Name: "Jordi Boggiano"},
+	}
+	if !reflect.DeepEqual(config.Authors, expected) {
+		t.Errorf("unexpected authors: %+v", config.Authors)
+	}
+}
+
+func TestTime(t *testing.T) {
+	tests := []struct {
+		Time     string
+		Expected time.Time
+		Error    bool
+	}{
+		{Time: "2020-05-01", Expected: time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{Time: "2020-05-01 10:20:30", Expected: time.Date(2020, 5, 1, 10, 20, 30, 0, time.UTC)},
+		{Time: "2020-05-01T10:20:30+00:00", Expected: time.Date(2020, 5, 1, 10, 20, 30, 0, time.UTC)},
+		{Time: "01.05.2020", Error: true},
+	}
+
+	for _, test := range tests {
+		data := []byte(`{"version": "1.0.0", "time": "` + test.Time + `"}`)
+		config, errs := NewConfigFromData(data, "composer.json")
+		if test.Error {
+			if errs.Len() != 1 || errs.Errors[0].Code != CodeInvalidTime {
+				t.Errorf("%s: expected an error, got %v", test.Time, errs)
+			}
+			continue
+		}
+
+		if errs != nil {
+			t.Errorf("%s: unexpected errors: %v", test.Time, errs)
+		}
+		if !config.Time.Equal(test.Expected) {
+			t.Errorf("%s: expected %v, got %v", test.Time, test.Expected, config.Time)
+		}
+	}
+}
+
+func TestSupport(t *testing.T) {
+	config, _ := NewConfigFromData([]byte(`{
+		"support": {
+			"issues": "https://github.com/i582/go-composer.json/issues",
+			"security": "https://example.com/security",
+			"irc": "irc://irc.freenode.org/composer"
+		}
+	}`), "composer.json")
+
+	expected := Support{
+		Issues:   "https://github.com/i582/go-composer.json/issues",
+		Security: "https://example.com/security",
+		Irc:      "irc://irc.freenode.org/composer",
+	}
+	if config.Support != expected {
+		t.Errorf("unexpected support: %+v", config.Support)
+	}
+}
+
+func TestReadOnly(t *testing.T) {
+	dir, err := ioutil.TempDir("", "composer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "composer.json")
+	data := []byte(`{"version": "1.0.0", "repositories": [{"type": "path", "url": "../lib"}]}`)
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config, errs := NewConfigFromFileWithOptions(path, LoadOptions{ReadOnly: true})
+	if errs != nil {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	config.AddCheckProvider(&ExternalCheckProvider{Command: "touch", Args: []string{filepath.Join(dir, "modified")}})
+	errs = config.CheckConfig()
+	if errs.Len() != 1 || errs.Errors[0].Code != CodeExternalCheckFailed {
+		t.Errorf("expected external checks to be disabled, got %v", errs)
+	}
+
+	repo := config.Reps[0]
+	resolved := repo.ResolveUrl("/project")
+	if resolved.Url != "/lib" || !resolved.Resolved {
+		t.Errorf("unexpected resolved repository: %+v", resolved)
+	}
+	if repo.Url != "../lib" || repo.Resolved {
+		t.Errorf("repository must not be modified in read-only mode: %+v", repo)
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Errorf("expected no files to be created, got %d files", len(files))
+	}
+	if content, _ := ioutil.ReadFile(path); string(content) != string(data) {
+		t.Errorf("config must not be modified")
+	}
+}
+
+func TestFunding(t *testing.T) {
+	config, _ := NewConfigFromData([]byte(`{
+		"funding": [
+			{"type": "patreon", "url": "https://www.patreon.com/phpdoctrine"},
+			{"type": "other", "url": "https://www.doctrine-project.org/sponsorship.html"}
+		]
+	}`), "composer.json")
+
+	expected := []FundingEntry{
+		{Type: "patreon", Url: "https://www.patreon.com/phpdoctrine"},
+		{Type: "other", Url: "https://www.doctrine-project.org/sponsorship.html"},
+	}
+	if !reflect.DeepEqual(config.Funding, expected) {
+		t.Errorf("unexpected funding: %+v", config.Funding)
+	}
+}
+
+func TestCustomUnmarshal(t *testing.T) {
+	var calls int
+	unmarshal := func(data []byte, v interface{}) error {
+		calls++
+		return json.Unmarshal(data, v)
+	}
+
+	config, errs := NewConfigFromDataWithOptions([]byte(`{"name": "my/package", "version": "1.0.0"}`), "composer.json", LoadOptions{
+		Unmarshal: unmarshal,
+	})
+	if errs != nil {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if config.Name != "my/package" || calls == 0 {
+		t.Errorf("expected the custom decoder to be used, got %d calls", calls)
+	}
+}
+
+func TestAbandoned(t *testing.T) {
+	tests := []struct {
+		abandoned   string
+		isAbandoned bool
+		replacement string
+	}{
+		{abandoned: `true`, isAbandoned: true},
+		{abandoned: `false`, isAbandoned: false},
+		{abandoned: `"monolog/monolog"`, isAbandoned: true, replacement: "monolog/monolog"},
+	}
+
+	for _, tt := range tests {
+		config, errs := NewConfigFromData([]byte(`{"version": "1.0.0", "abandoned": `+tt.abandoned+`}`), "composer.json")
+		if errs != nil {
+			t.Fatalf("unexpected errors: %v", errs)
+		}
+		if config.IsAbandoned() != tt.isAbandoned || config.ReplacementPackage() != tt.replacement {
+			t.Errorf("abandoned %s: got %v, %q", tt.abandoned, config.IsAbandoned(), config.ReplacementPackage())
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package composer
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ComposerVersion is the major version of composer
+// a project targets, see DetectComposerVersion.
+type ComposerVersion struct {
+	// Major is 1 or 2, or 0 if there is no evidence of either.
+	Major int
+	// Reason describes the evidence the version is inferred from.
+	Reason string
+}
+
+// DetectComposerVersion infers whether the project of the config
+// targets composer 1 or 2. The lock may be nil.
+//
+// The evidence is taken, in order of reliability, from the format
+// of the lock, the format of the installed.json in the vendor dir,
+// the composer-plugin-api requirement and the allow-plugins setting,
+// which only composer 2 knows.
+func DetectComposerVersion(c *Config, lock *Lock) ComposerVersion {
+	if lock != nil {
+		reason := "the lock has no plugin-api-version"
+		if lock.PluginApiVersion != "" {
+			reason = fmt.Sprintf("the lock has plugin-api-version %s", lock.PluginApiVersion)
+		}
+		if lock.IsLegacy() {
+			return ComposerVersion{Major: 1, Reason: reason}
+		}
+		return ComposerVersion{Major: 2, Reason: reason}
+	}
+
+	if installed, err := LoadInstalled(c.InstalledPath()); err == nil {
+		if installed.Legacy {
+			return ComposerVersion{Major: 1, Reason: "installed.json is a bare list of packages"}
+		}
+		return ComposerVersion{Major: 2, Reason: "installed.json has a packages key"}
+	}
+
+	for _, require := range []map[string]string{c.Require, c.RequireDev} {
+		raw, ok := require["composer-plugin-api"]
+		if !ok {
+			continue
+		}
+		constraint, err := ParseConstraint(raw)
+		if err != nil {
+			continue
+		}
+
+		allows1, allows2 := constraint.AllowsMajor(1), constraint.AllowsMajor(2)
+		switch {
+		case allows1 && !allows2:
+			return ComposerVersion{Major: 1, Reason: fmt.Sprintf("composer-plugin-api is required as %s", raw)}
+		case allows2 && !allows1:
+			return ComposerVersion{Major: 2, Reason: fmt.Sprintf("composer-plugin-api is required as %s", raw)}
+		}
+	}
+
+	if c.Settings.AllowPlugins.All != nil || c.Settings.AllowPlugins.Plugins != nil {
+		return ComposerVersion{Major: 2, Reason: "config.allow-plugins is set"}
+	}
+
+	return ComposerVersion{}
+}
+
+// CodePluginNotAllowed is the code of the errors reported
+// for locked plugins blocked by config.allow-plugins.
+const CodePluginNotAllowed = "plugin-not-allowed"
+
+// AllowPluginsCheck is a check provider that reports the plugins
+// in the lock that are not allowed by config.allow-plugins, which
+// composer 2.2 and later refuses to run.
+//
+// The check is skipped unless DetectComposerVersion infers that
+// the project targets composer 2, since composer 1 runs all plugins.
+type AllowPluginsCheck struct {
+	// Lock is the lock of the config.
+	Lock *Lock
+}
+
+// Check implements the CheckProvider interface.
+func (a AllowPluginsCheck) Check(c *Config) []*ConfigError {
+	if a.Lock == nil || DetectComposerVersion(c, a.Lock).Major != 2 {
+		return nil
+	}
+
+	var names []string
+	for _, pkg := range a.Lock.AllPackages() {
+		if pkg.Type == TypeComposerPlugin && !c.Settings.AllowPlugins.Allows(pkg.Name) {
+			names = append(names, pkg.Name)
+		}
+	}
+	sort.Strings(names)
+
+	var errors []*ConfigError
+	for _, name := range names {
+		errors = append(errors, &ConfigError{
+			Msg:      fmt.Sprintf("plugin %s is not allowed by config.allow-plugins and is not run by composer 2.2+", name),
+			Critical: false,
+			Code:     CodePluginNotAllowed,
+			Pointer:  "/config/allow-plugins",
+		})
+	}
+	return errors
+}
+
+// Allows returns true if the plugin with the passed name is allowed.
+//
+// Patterns may contain * wildcards, as "symfony/*". If several patterns
+// match, the most specific one, that is, the longest, takes precedence.
+func (a AllowPlugins) Allows(name string) bool {
+	if a.All != nil {
+		return *a.All
+	}
+
+	allowed, best := false, -1
+	for pattern, allow := range a.Plugins {
+		if len(pattern) <= best || !pluginPatternRegexp(pattern).MatchString(name) {
+			continue
+		}
+		allowed, best = allow, len(pattern)
+	}
+	return allowed
+}
+
+// pluginPatternRegexp returns the regexp matching the package
+// names of the allow-plugins pattern, case-insensitively.
+func pluginPatternRegexp(pattern string) *regexp.Regexp {
+	quoted := strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, `.*`)
+	return regexp.MustCompile(`(?i)^` + quoted + `$`)
+}
@@ -0,0 +1,75 @@
+package composer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDetectComposerVersion(t *testing.T) {
+	empty := t.TempDir()
+	allowAll := true
+
+	tests := []struct {
+		Config *Config
+		Lock   *Lock
+		Major  int
+	}{
+		{Config: &Config{RootDir: empty}, Lock: &Lock{PluginApiVersion: "2.6.0"}, Major: 2},
+		{Config: &Config{RootDir: empty}, Lock: &Lock{}, Major: 1},
+		{Config: &Config{RootDir: empty}, Lock: &Lock{PluginApiVersion: "1.1.0"}, Major: 1},
+		{Config: &Config{RootDir: empty, Require: map[string]string{"composer-plugin-api": "^1.1"}}, Major: 1},
+		{Config: &Config{RootDir: empty, Require: map[string]string{"composer-plugin-api": "^2.0"}}, Major: 2},
+		{Config: &Config{RootDir: empty, Require: map[string]string{"composer-plugin-api": "^1.0 || ^2.0"}}, Major: 0},
+		{Config: &Config{RootDir: empty, Settings: ComposerConfig{AllowPlugins: AllowPlugins{All: &allowAll}}}, Major: 2},
+		{Config: &Config{RootDir: empty}, Major: 0},
+	}
+
+	for _, test := range tests {
+		detected := DetectComposerVersion(test.Config, test.Lock)
+		if detected.Major != test.Major {
+			t.Errorf("expected composer %d, got %d (%s)", test.Major, detected.Major, detected.Reason)
+		}
+	}
+
+	legacy := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(legacy, "vendor", "composer"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(legacy, "vendor", "composer", "installed.json"), []byte(`[]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if detected := DetectComposerVersion(&Config{RootDir: legacy}, nil); detected.Major != 1 {
+		t.Errorf("expected composer 1 from installed.json, got %d", detected.Major)
+	}
+
+	lock := &Lock{
+		PluginApiVersion: "2.6.0",
+		Packages: []LockPackage{
+			{Name: "symfony/flex", Type: TypeComposerPlugin},
+			{Name: "phpstan/extension-installer", Type: TypeComposerPlugin},
+			{Name: "psr/log", Type: TypeLibrary},
+		},
+	}
+	config := &Config{
+		RootDir: empty,
+		Settings: ComposerConfig{AllowPlugins: AllowPlugins{Plugins: map[string]bool{
+			"symfony/*":    false,
+			"Symfony/Flex": true,
+		}}},
+	}
+
+	errs := AllowPluginsCheck{Lock: lock}.Check(config)
+	if len(errs) != 1 || errs[0].Code != CodePluginNotAllowed || !strings.Contains(errs[0].Msg, "phpstan/extension-installer") {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+
+	for _, api := range []string{"", "1.1.0"} {
+		lock.PluginApiVersion = api
+		if errs := (AllowPluginsCheck{Lock: lock}).Check(config); len(errs) != 0 {
+			t.Errorf("expected no errors for composer 1 with plugin-api-version %q, got %v", api, errs)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package composer
+
+import (
+	"github.com/i582/go-composer.json/internal/version"
+)
+
+// ParseConstraint parses the version constraint of a requirement.
+//
+// Example:
+//
+//	c, err := composer.ParseConstraint(config.Require["monolog/monolog"])
+//	if err == nil && c.AllowsMajor(2) {
+//	  ...
+//	}
+func ParseConstraint(raw string) (*version.Constraint, error) {
+	return version.NewConstraint(raw)
+}
+
+// NormalizeVersion returns the version in the normalized form
+// used by composer, for example, in composer.lock.
+//
+// Example:
+//
+//	composer.NormalizeVersion("v1.2.3-RC1") // 1.2.3.0-RC1
+//	composer.NormalizeVersion("2.x-dev")    // 2.9999999.9999999.9999999-dev
+func NormalizeVersion(raw string) (string, error) {
+	return version.Normalize(raw)
+}
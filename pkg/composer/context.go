@@ -0,0 +1,202 @@
+package composer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// ContextCheck is a custom check that receives a CheckContext
+// instead of the bare config, see Config.RegisterCheck.
+type ContextCheck func(ctx *CheckContext) []*ConfigError
+
+// CheckContext is passed to the checks added with Config.RegisterCheck.
+//
+// A single context is shared by all checks of one Config.CheckConfig
+// call, and the data that is expensive to compute, such as the
+// classmap, the workspace configs, the lock and the files, is computed
+// on first use and cached, so that checks do not rebuild it themselves.
+type CheckContext struct {
+	// Config is the config being checked.
+	Config *Config
+
+	mu    sync.Mutex
+	files map[string]fileResult
+	stats map[string]statResult
+
+	classmapOnce sync.Once
+	classmap     map[string]string
+	classmapErr  error
+
+	workspaceOnce sync.Once
+	workspace     []*Config
+
+	lockOnce sync.Once
+	lock     *Lock
+	lockErr  error
+
+	lockGraphOnce sync.Once
+	lockGraph     *LockGraph
+}
+
+type fileResult struct {
+	data []byte
+	err  error
+}
+
+type statResult struct {
+	info os.FileInfo
+	err  error
+}
+
+// newCheckContext returns an empty context for the config.
+func newCheckContext(c *Config) *CheckContext {
+	return &CheckContext{
+		Config: c,
+		files:  map[string]fileResult{},
+		stats:  map[string]statResult{},
+	}
+}
+
+// RegisterCheck adds custom check that receives a CheckContext.
+//
+// Example:
+//
+//	cfg.RegisterCheck(func(ctx *composer.CheckContext) []*composer.ConfigError {
+//	  classmap, err := ctx.Classmap()
+//	  ...
+//	})
+func (c *Config) RegisterCheck(check ContextCheck) {
+	c.ContextChecks = append(c.ContextChecks, check)
+}
+
+// ReadFile returns the content of the file, the path is relative
+// to the config root. The result is cached for the context.
+func (ctx *CheckContext) ReadFile(path string) ([]byte, error) {
+	path = ctx.abs(path)
+
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+
+	if res, ok := ctx.files[path]; ok {
+		return res.data, res.err
+	}
+
+	data, err := ioutil.ReadFile(path)
+	ctx.files[path] = fileResult{data: data, err: err}
+	return data, err
+}
+
+// Stat returns the info of the file, the path is relative
+// to the config root. The result is cached for the context.
+func (ctx *CheckContext) Stat(path string) (os.FileInfo, error) {
+	path = ctx.abs(path)
+
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+
+	if res, ok := ctx.stats[path]; ok {
+		return res.info, res.err
+	}
+
+	info, err := os.Stat(path)
+	ctx.stats[path] = statResult{info: info, err: err}
+	return info, err
+}
+
+// Classmap returns the classmap of the config, see Config.Classmap.
+// It is built once for the context.
+func (ctx *CheckContext) Classmap() (map[string]string, error) {
+	ctx.classmapOnce.Do(func() {
+		ctx.classmap, ctx.classmapErr = ctx.Config.Classmap()
+	})
+	return ctx.classmap, ctx.classmapErr
+}
+
+// Workspace returns the configs of the packages in the path
+// repositories of the config, sorted by path. Packages that
+// cannot be loaded are skipped. The configs are loaded once
+// for the context, in read-only mode if the config is read-only.
+func (ctx *CheckContext) Workspace() []*Config {
+	ctx.workspaceOnce.Do(func() {
+		ctx.workspace = ctx.Config.loadWorkspace()
+	})
+	return ctx.workspace
+}
+
+// Lock returns the lock of the config, see Config.LockPath.
+// It is read once for the context.
+func (ctx *CheckContext) Lock() (*Lock, error) {
+	ctx.lockOnce.Do(func() {
+		ctx.lock, ctx.lockErr = LoadLock(ctx.Config.LockPath())
+	})
+	return ctx.lock, ctx.lockErr
+}
+
+// LockGraph returns the graph of the packages of the lock of the
+// config, see NewLockGraph. It is built once for the context.
+func (ctx *CheckContext) LockGraph() (*LockGraph, error) {
+	lock, err := ctx.Lock()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx.lockGraphOnce.Do(func() {
+		ctx.lockGraph = NewLockGraph(lock)
+	})
+	return ctx.lockGraph, nil
+}
+
+// loadWorkspace loads the configs of the path repositories,
+// the urls may contain wildcards, as in composer.
+func (c *Config) loadWorkspace() []*Config {
+	var paths []string
+	for _, repo := range c.Reps {
+		if repo.Type != "path" {
+			continue
+		}
+
+		matches, err := filepath.Glob(filepath.FromSlash(repo.ResolvedUrl(c.RootDir)))
+		if err != nil {
+			continue
+		}
+		paths = append(paths, matches...)
+	}
+	sort.Strings(paths)
+
+	var configs []*Config
+	seen := map[string]bool{}
+	for _, dir := range paths {
+		if seen[dir] {
+			continue
+		}
+		seen[dir] = true
+
+		config, errs := NewConfigFromFileWithOptions(filepath.Join(dir, "composer.json"), LoadOptions{ReadOnly: c.ReadOnly})
+		if errs != nil && hasCriticalError(errs) {
+			continue
+		}
+		configs = append(configs, config)
+	}
+
+	return configs
+}
+
+// hasCriticalError reports whether the errors contain a critical one.
+func hasCriticalError(errs *ConfigErrors) bool {
+	for _, err := range errs.Errors {
+		if err.Critical {
+			return true
+		}
+	}
+	return false
+}
+
+func (ctx *CheckContext) abs(path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(ctx.Config.RootDir, filepath.FromSlash(path))
+}
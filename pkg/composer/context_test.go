@@ -0,0 +1,102 @@
+package composer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRegisterCheck(t *testing.T) {
+	dir, err := ioutil.TempDir("", "composer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"composer.json":                 `{"version": "1.0.0", "repositories": [{"type": "path", "url": "packages/*"}]}`,
+		"packages/a/composer.json":      `{"name": "my/a", "version": "1.0.0"}`,
+		"packages/b/composer.json":      `{"name": "my/b", "version": "1.0.0"}`,
+		"packages/broken/composer.json": `{"name": `,
+		"packages/readme/.gitkeep":      ``,
+	}
+	for file, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config, errs := NewConfigFromFile(filepath.Join(dir, "composer.json"))
+	if errs != nil {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	var contexts []*CheckContext
+	check := func(ctx *CheckContext) []*ConfigError {
+		contexts = append(contexts, ctx)
+
+		var errors []*ConfigError
+		for _, pkg := range ctx.Workspace() {
+			errors = append(errors, &ConfigError{Msg: pkg.Name})
+		}
+		return errors
+	}
+	config.RegisterCheck(check)
+	config.RegisterCheck(check)
+
+	errs = config.CheckConfig()
+	if errs.Len() != 4 || errs.Errors[0].Msg != "my/a" || errs.Errors[1].Msg != "my/b" {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+	if len(contexts) != 2 || contexts[0] != contexts[1] {
+		t.Errorf("expected a single context shared by the checks")
+	}
+
+	ctx := contexts[0]
+	data, err := ctx.ReadFile("composer.json")
+	if err != nil || !strings.Contains(string(data), "repositories") {
+		t.Errorf("unexpected content: %s, %v", data, err)
+	}
+	if err := os.Remove(filepath.Join(dir, "composer.json")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ctx.ReadFile("composer.json"); err != nil {
+		t.Errorf("expected cached content, got %v", err)
+	}
+
+	if _, err := ctx.Lock(); err == nil {
+		t.Errorf("expected an error for a missing lock")
+	}
+
+	lockData := `{"packages": [
+		{"name": "app/core", "require": {"php": ">=8.1", "psr/log-implementation": "^3.0"}},
+		{"name": "monolog/monolog", "provide": {"psr/log-implementation": "3.0.0"}, "require": {"psr/log": "^3.0"}},
+		{"name": "psr/log"}
+	]}`
+	if err := ioutil.WriteFile(filepath.Join(dir, "composer.lock"), []byte(lockData), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx = newCheckContext(config)
+	graph, err := ctx.LockGraph()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := ctx.LockGraph(); again != graph {
+		t.Errorf("expected the lock graph to be cached")
+	}
+	if deps := graph.Dependencies("App/Core"); !reflect.DeepEqual(deps, []string{"monolog/monolog"}) {
+		t.Errorf("unexpected dependencies: %v", deps)
+	}
+	reachable := graph.Reachable(map[string]string{"app/core": "*"})
+	if !reflect.DeepEqual(reachable, []string{"app/core", "monolog/monolog", "psr/log"}) {
+		t.Errorf("unexpected reachable packages: %v", reachable)
+	}
+}
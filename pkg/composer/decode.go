@@ -0,0 +1,107 @@
+package composer
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// CodeInvalidFieldType is the code of the errors reported
+// when a field of the config has a value of the wrong type.
+const CodeInvalidFieldType = "invalid-field-type"
+
+// decodeErrors returns the located errors for the error of decoding
+// the config. Each top-level field is decoded separately to find all
+// the fields with a value of the wrong type, not just the first one.
+//
+// Syntax errors are returned as is, since the fields cannot be found.
+func decodeErrors(data []byte, err error, unmarshal func([]byte, interface{}) error) []*ConfigError {
+	generic := []*ConfigError{{
+		Msg:      err.Error(),
+		Critical: true,
+	}}
+
+	if _, isSyntaxError := err.(*json.SyntaxError); isSyntaxError {
+		return generic
+	}
+
+	var root map[string]json.RawMessage
+	if json.Unmarshal(data, &root) != nil {
+		return generic
+	}
+
+	var errors []*ConfigError
+	for _, key := range sortedRawKeys(root) {
+		field, err := json.Marshal(map[string]json.RawMessage{key: root[key]})
+		if err != nil {
+			continue
+		}
+
+		config := Config{Settings: DefaultComposerConfig()}
+		if err := unmarshal(field, &config); err != nil {
+			errors = append(errors, fieldError(key, err))
+		}
+	}
+
+	if len(errors) == 0 {
+		return generic
+	}
+	return errors
+}
+
+// fieldError returns the error for the top-level field
+// that cannot be decoded.
+func fieldError(key string, err error) *ConfigError {
+	typeErr, isTypeError := err.(*json.UnmarshalTypeError)
+	if !isTypeError {
+		return &ConfigError{
+			Msg:      fmt.Sprintf("field '%s': %v", key, err),
+			Critical: true,
+			Code:     CodeInvalidFieldType,
+			Pointer:  "/" + escapePointer(key),
+		}
+	}
+
+	path := typeErr.Field
+	if path == "" {
+		path = key
+	}
+
+	segments := strings.Split(path, ".")
+	for i, segment := range segments {
+		segments[i] = escapePointer(segment)
+	}
+
+	return &ConfigError{
+		Msg:      fmt.Sprintf("field '%s' must be %s, got %s", path, describeType(typeErr.Type), typeErr.Value),
+		Critical: true,
+		Code:     CodeInvalidFieldType,
+		Pointer:  "/" + strings.Join(segments, "/"),
+	}
+}
+
+// describeType returns the JSON type expected for the Go type.
+func describeType(typ reflect.Type) string {
+	if typ == nil {
+		return "a value of another type"
+	}
+
+	switch typ.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Map, reflect.Struct:
+		return "an object"
+	case reflect.Ptr:
+		return describeType(typ.Elem())
+	}
+	return "a value of another type"
+}
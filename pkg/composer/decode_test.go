@@ -0,0 +1,66 @@
+package composer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLenientLoading(t *testing.T) {
+	data := []byte(`{"name": "my/package", "description": ["wrong"], "version": "1.0.0"}`)
+
+	_, errs := NewConfigFromData(data, "composer.json")
+	if errs == nil || errs.Len() != 1 || !errs.Errors[0].Critical {
+		t.Fatalf("expected a single critical error, got %v", errs)
+	}
+
+	config, errs := NewConfigFromDataWithOptions(data, "composer.json", LoadOptions{Lenient: true})
+	if errs == nil || errs.Len() != 1 || !errs.Errors[0].Critical {
+		t.Fatalf("expected a single critical error, got %v", errs)
+	}
+	if config.Name != "my/package" || config.Version == nil {
+		t.Errorf("expected the rest of the config to be loaded")
+	}
+
+	data = []byte(`{
+		"description": ["wrong"],
+		"version": "1.0.0",
+		"extra": {"composer-check": {"ignore": ["invalid-field-type"]}}
+	}`)
+	_, errs = NewConfigFromDataWithOptions(data, "composer.json", LoadOptions{Lenient: true})
+	if errs != nil && errs.Len() != 0 {
+		t.Errorf("expected the type error to be suppressed, got %v", errs)
+	}
+
+	_, errs = NewConfigFromDataWithOptions([]byte(`{"name": `), "composer.json", LoadOptions{Lenient: true})
+	if errs == nil || errs.Len() != 1 || !errs.Errors[0].Critical {
+		t.Errorf("expected a syntax error to stop loading, got %v", errs)
+	}
+}
+
+func TestFieldTypeErrors(t *testing.T) {
+	_, errs := NewConfigFromData([]byte(`{
+		"version": "1.0.0",
+		"description": ["wrong"],
+		"require": ["foo/bar"],
+		"autoload": {"files": "helpers.php"},
+		"license": 1
+	}`), "composer.json")
+
+	var actual []string
+	for _, err := range errs.Errors {
+		if err.Code == CodeInvalidFieldType {
+			actual = append(actual, err.Pointer+" "+err.Msg)
+		}
+	}
+
+	expected := []string{
+		"/autoload/files field 'autoload.files' must be an array, got string",
+		"/description field 'description' must be a string, got array",
+		"/license field 'license': license must be a string or an array of strings",
+		"/require field 'require' must be an object, got array",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("mismatch errors:\nwant: %v\nhave: %v", strings.Join(expected, "\n"), strings.Join(actual, "\n"))
+	}
+}
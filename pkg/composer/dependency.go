@@ -0,0 +1,71 @@
+package composer
+
+import (
+	"github.com/i582/go-composer.json/internal/version"
+)
+
+// AllowsStability reports whether the version is acceptable
+// under the minimum-stability of the config.
+//
+// If minimum-stability is not set or invalid, stable is used,
+// as in composer. Patch versions are considered stable.
+func (c *Config) AllowsStability(v *version.Version) bool {
+	minimum, ok := version.ParseStability(c.MinimumStability)
+	if !ok {
+		minimum = version.StabilityStable
+	}
+	return v.Stability() >= minimum
+}
+
+// ConflictsWith reports whether the package with the passed
+// version is declared as conflicting in the conflict section.
+//
+// Constraints that cannot be parsed are ignored.
+func (c *Config) ConflictsWith(name string, v *version.Version) bool {
+	constraint, ok := c.Conflict[name]
+	if !ok {
+		return false
+	}
+
+	return c.constraintAllows(constraint, v)
+}
+
+// Replaces reports whether the package with the passed
+// version is replaced by this package in the replace section.
+//
+// The self.version constraint is resolved to the version of this package.
+// Constraints that cannot be parsed are ignored.
+func (c *Config) Replaces(name string, v *version.Version) bool {
+	constraint, ok := c.Replace[name]
+	if !ok {
+		return false
+	}
+
+	return c.constraintAllows(constraint, v)
+}
+
+// Provides reports whether the package (including virtual and
+// platform packages like ext-mbstring) with the passed version
+// is provided by this package in the provide section.
+//
+// The self.version constraint is resolved to the version of this package.
+// Constraints that cannot be parsed are ignored.
+func (c *Config) Provides(name string, v *version.Version) bool {
+	constraint, ok := c.Provide[name]
+	if !ok {
+		return false
+	}
+
+	return c.constraintAllows(constraint, v)
+}
+
+// constraintAllows reports whether the constraint from one of the
+// dependency sections allows the version.
+func (c *Config) constraintAllows(constraint string, v *version.Version) bool {
+	parsed, err := version.NewConstraintFor(constraint, c.Version)
+	if err != nil {
+		return false
+	}
+
+	return parsed.Allows(v)
+}
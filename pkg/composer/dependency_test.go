@@ -0,0 +1,124 @@
+package composer
+
+import (
+	"testing"
+
+	"github.com/i582/go-composer.json/internal/version"
+)
+
+func TestConflictsWith(t *testing.T) {
+	config, _ := NewConfigFromData([]byte(`{"conflict": {"foo/bar": "<1.2 || 2.0.0"}}`), "composer.json")
+
+	tests := []struct {
+		Name     string
+		Version  string
+		Expected bool
+	}{
+		{Name: "foo/bar", Version: "1.1.0", Expected: true},
+		{Name: "foo/bar", Version: "2.0.0", Expected: true},
+		{Name: "foo/bar", Version: "1.2.0", Expected: false},
+		{Name: "foo/baz", Version: "1.0.0", Expected: false},
+	}
+
+	for _, test := range tests {
+		v, _ := version.NewVersion(test.Version)
+		if res := config.ConflictsWith(test.Name, v); res != test.Expected {
+			t.Errorf("%s %s: expected %v, got %v", test.Name, test.Version, test.Expected, res)
+		}
+	}
+}
+
+func TestReplaces(t *testing.T) {
+	config, _ := NewConfigFromData([]byte(`{
+		"version": "2.1.0",
+		"replace": {"my/sub-a": "self.version", "my/sub-b": "^1.0"}
+	}`), "composer.json")
+
+	tests := []struct {
+		Name     string
+		Version  string
+		Expected bool
+	}{
+		{Name: "my/sub-a", Version: "2.1.0", Expected: true},
+		{Name: "my/sub-a", Version: "2.0.0", Expected: false},
+		{Name: "my/sub-b", Version: "1.5.0", Expected: true},
+		{Name: "my/sub-b", Version: "2.1.0", Expected: false},
+		{Name: "my/sub-c", Version: "2.1.0", Expected: false},
+	}
+
+	for _, test := range tests {
+		v, _ := version.NewVersion(test.Version)
+		if res := config.Replaces(test.Name, v); res != test.Expected {
+			t.Errorf("%s %s: expected %v, got %v", test.Name, test.Version, test.Expected, res)
+		}
+	}
+}
+
+func TestProvides(t *testing.T) {
+	config, _ := NewConfigFromData([]byte(`{
+		"version": "1.0.0",
+		"provide": {"psr/log-implementation": "1.0.0", "ext-mbstring": "*"}
+	}`), "composer.json")
+
+	tests := []struct {
+		Name     string
+		Version  string
+		Expected bool
+	}{
+		{Name: "psr/log-implementation", Version: "1.0.0", Expected: true},
+		{Name: "psr/log-implementation", Version: "2.0.0", Expected: false},
+		{Name: "ext-mbstring", Version: "7.4.0", Expected: true},
+		{Name: "ext-intl", Version: "7.4.0", Expected: false},
+	}
+
+	for _, test := range tests {
+		v, _ := version.NewVersion(test.Version)
+		if res := config.Provides(test.Name, v); res != test.Expected {
+			t.Errorf("%s %s: expected %v, got %v", test.Name, test.Version, test.Expected, res)
+		}
+	}
+}
+
+func TestMinimumStability(t *testing.T) {
+	tests := []struct {
+		MinimumStability string
+		Allowed          []string
+		Disallowed       []string
+	}{
+		{
+			MinimumStability: "",
+			Allowed:          []string{"1.0.0", "1.0.0-patch1"},
+			Disallowed:       []string{"1.0.0-RC1", "1.0.0-dev"},
+		},
+		{
+			MinimumStability: "beta",
+			Allowed:          []string{"1.0.0-beta2", "1.0.0-RC1", "1.0.0"},
+			Disallowed:       []string{"1.0.0-alpha", "1.0.0-dev"},
+		},
+		{
+			MinimumStability: "dev",
+			Allowed:          []string{"1.0.0-dev", "1.0.0"},
+		},
+	}
+
+	for _, test := range tests {
+		config := &Config{MinimumStability: test.MinimumStability}
+		for _, raw := range test.Allowed {
+			v, _ := version.NewVersion(raw)
+			if !config.AllowsStability(v) {
+				t.Errorf("%s: expected %s to be allowed", test.MinimumStability, raw)
+			}
+		}
+		for _, raw := range test.Disallowed {
+			v, _ := version.NewVersion(raw)
+			if config.AllowsStability(v) {
+				t.Errorf("%s: expected %s to be disallowed", test.MinimumStability, raw)
+			}
+		}
+	}
+
+	_, errs := NewConfigFromData([]byte(`{"version": "1.0.0", "minimum-stability": "unstable"}`), "composer.json")
+	if errs.Len() != 1 || errs.Errors[0].Code != CodeInvalidMinimumStability {
+		t.Errorf("expected an error for invalid minimum-stability, got %v", errs)
+	}
+}
@@ -0,0 +1,64 @@
+package composer
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// PackageInfo is the information about a locked package,
+// the same as composer show prints, see Config.Describe.
+type PackageInfo struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description,omitempty"`
+	Type        string  `json:"type,omitempty"`
+	Homepage    string  `json:"homepage,omitempty"`
+	Version     string  `json:"version"`
+	Time        string  `json:"time,omitempty"`
+	License     License `json:"license,omitempty"`
+	// Dev is true if the package is installed only in development.
+	Dev    bool           `json:"dev"`
+	Source *PackageSource `json:"source,omitempty"`
+	Dist   *PackageSource `json:"dist,omitempty"`
+
+	Require    map[string]string `json:"require,omitempty"`
+	RequireDev map[string]string `json:"require-dev,omitempty"`
+	Suggest    map[string]string `json:"suggest,omitempty"`
+
+	// Path is the absolute install path of the package
+	// in the vendor dir, including the legacy target-dir.
+	Path string `json:"path"`
+	// Installed is true if the install path exists.
+	Installed bool `json:"installed"`
+}
+
+// Describe returns the information about the package locked
+// in the lock of the config, as composer show does.
+//
+// If the package is not locked, ok is false.
+func (c *Config) Describe(lock *Lock, name string) (info *PackageInfo, ok bool) {
+	pkg, dev, ok := lock.Package(name)
+	if !ok {
+		return nil, false
+	}
+
+	path := filepath.Join(c.VendorDir(), filepath.FromSlash(pkg.Name), filepath.FromSlash(pkg.TargetDir))
+	_, err := os.Stat(path)
+
+	return &PackageInfo{
+		Name:        pkg.Name,
+		Description: pkg.Description,
+		Type:        pkg.Type,
+		Homepage:    pkg.Homepage,
+		Version:     pkg.Version,
+		Time:        pkg.Time,
+		License:     pkg.License,
+		Dev:         dev,
+		Source:      pkg.Source,
+		Dist:        pkg.Dist,
+		Require:     pkg.Require,
+		RequireDev:  pkg.RequireDev,
+		Suggest:     pkg.Suggest,
+		Path:        path,
+		Installed:   err == nil,
+	}, true
+}
@@ -0,0 +1,47 @@
+package composer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDescribe(t *testing.T) {
+	dir, err := ioutil.TempDir("", "composer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := os.MkdirAll(filepath.Join(dir, "vendor", "psr", "log"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	config := &Config{RootDir: dir}
+	lock := &Lock{
+		Packages: []LockPackage{
+			{Name: "psr/log", Version: "3.0.0", License: License{"MIT"}, Require: map[string]string{"php": ">=8.0.0"}},
+		},
+		PackagesDev: []LockPackage{
+			{Name: "symfony/yaml", Version: "2.0.0", TargetDir: "Symfony/Component/Yaml"},
+		},
+	}
+
+	info, ok := config.Describe(lock, "psr/log")
+	if !ok || info.Version != "3.0.0" || info.Dev || !info.Installed || info.Require["php"] != ">=8.0.0" {
+		t.Errorf("unexpected info: %+v", info)
+	}
+	if info.Path != filepath.Join(dir, "vendor", "psr", "log") {
+		t.Errorf("unexpected path: %s", info.Path)
+	}
+
+	info, ok = config.Describe(lock, "symfony/yaml")
+	if !ok || !info.Dev || info.Installed || info.Path != filepath.Join(dir, "vendor", "symfony", "yaml", "Symfony", "Component", "Yaml") {
+		t.Errorf("unexpected info: %+v", info)
+	}
+
+	if _, ok := config.Describe(lock, "foo/bar"); ok {
+		t.Errorf("expected foo/bar not to be found")
+	}
+}
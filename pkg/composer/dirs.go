@@ -0,0 +1,89 @@
+package composer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// VendorDir returns the absolute path to the vendor dir.
+//
+// The COMPOSER_VENDOR_DIR env var takes precedence over
+// the config.vendor-dir setting, as in composer.
+func (c *Config) VendorDir() string {
+	dir := firstNonEmpty(os.Getenv("COMPOSER_VENDOR_DIR"), c.Settings.VendorDir, "vendor")
+	return c.absDir(dir)
+}
+
+// BinDir returns the absolute path to the dir
+// where binaries of dependencies are linked.
+//
+// The COMPOSER_BIN_DIR env var takes precedence over
+// the config.bin-dir setting, as in composer.
+func (c *Config) BinDir() string {
+	dir := firstNonEmpty(os.Getenv("COMPOSER_BIN_DIR"), c.Settings.BinDir)
+	if dir == "" {
+		return filepath.Join(c.VendorDir(), "bin")
+	}
+
+	dir = strings.ReplaceAll(dir, "{$vendor-dir}", c.VendorDir())
+	return c.absDir(dir)
+}
+
+// CacheDir returns the absolute path to the composer cache dir.
+//
+// The COMPOSER_CACHE_DIR env var takes precedence over
+// the config.cache-dir setting, as in composer.
+func (c *Config) CacheDir() string {
+	dir := firstNonEmpty(os.Getenv("COMPOSER_CACHE_DIR"), c.Settings.CacheDir)
+	if dir != "" {
+		return c.absDir(dir)
+	}
+
+	if home := os.Getenv("COMPOSER_HOME"); home != "" {
+		return filepath.Join(home, "cache")
+	}
+
+	userCache, err := os.UserCacheDir()
+	if err != nil {
+		return c.absDir(filepath.Join(composerHome(), "cache"))
+	}
+	return filepath.Join(userCache, "composer")
+}
+
+// absDir expands the placeholders in the dir and makes it
+// absolute relative to the config root.
+func (c *Config) absDir(dir string) string {
+	dir = strings.ReplaceAll(dir, "{$home}", composerHome())
+
+	if dir == "~" || strings.HasPrefix(dir, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			dir = filepath.Join(home, dir[1:])
+		}
+	}
+
+	if filepath.IsAbs(dir) {
+		return filepath.Clean(dir)
+	}
+	return filepath.Join(c.RootDir, dir)
+}
+
+// composerHome returns the composer home dir.
+func composerHome() string {
+	if home := os.Getenv("COMPOSER_HOME"); home != "" {
+		return home
+	}
+	if configDir, err := os.UserConfigDir(); err == nil {
+		return filepath.Join(configDir, "composer")
+	}
+	return ".composer"
+}
+
+func firstNonEmpty(vals ...string) string {
+	for _, val := range vals {
+		if val != "" {
+			return val
+		}
+	}
+	return ""
+}
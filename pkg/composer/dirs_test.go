@@ -0,0 +1,50 @@
+package composer
+
+import (
+	"os"
+	"testing"
+)
+
+func TestDirs(t *testing.T) {
+	vars := []string{"COMPOSER_VENDOR_DIR", "COMPOSER_BIN_DIR", "COMPOSER_HOME", "COMPOSER_CACHE_DIR"}
+	for _, name := range vars {
+		if value, ok := os.LookupEnv(name); ok {
+			defer os.Setenv(name, value)
+		} else {
+			defer os.Unsetenv(name)
+		}
+		os.Unsetenv(name)
+	}
+	os.Setenv("COMPOSER_HOME", "/composer-home")
+
+	config := &Config{RootDir: "/project"}
+	if dir := config.VendorDir(); dir != "/project/vendor" {
+		t.Errorf("unexpected vendor dir: %s", dir)
+	}
+	if dir := config.BinDir(); dir != "/project/vendor/bin" {
+		t.Errorf("unexpected bin dir: %s", dir)
+	}
+	if dir := config.CacheDir(); dir != "/composer-home/cache" {
+		t.Errorf("unexpected cache dir: %s", dir)
+	}
+
+	config.Settings = ComposerConfig{
+		VendorDir: "lib/vendor",
+		BinDir:    "{$vendor-dir}/../bin",
+		CacheDir:  "{$home}/my-cache",
+	}
+	if dir := config.VendorDir(); dir != "/project/lib/vendor" {
+		t.Errorf("unexpected vendor dir: %s", dir)
+	}
+	if dir := config.BinDir(); dir != "/project/lib/bin" {
+		t.Errorf("unexpected bin dir: %s", dir)
+	}
+	if dir := config.CacheDir(); dir != "/composer-home/my-cache" {
+		t.Errorf("unexpected cache dir: %s", dir)
+	}
+
+	os.Setenv("COMPOSER_VENDOR_DIR", "/other/vendor")
+	if dir := config.VendorDir(); dir != "/other/vendor" {
+		t.Errorf("unexpected vendor dir: %s", dir)
+	}
+}
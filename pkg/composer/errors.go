@@ -1,16 +1,19 @@
 package composer
 
-import (
-	"fmt"
-)
-
 // ConfigError structure describes one error in the config.
 //
 // If the Critical flag is true, then the analysis
 // process will be interrupted.
+//
+// Code is an identifier of the rule that produced the error,
+// Pointer is a JSON pointer to the place in the config the error
+// refers to. Both are optional and are used to suppress errors,
+// see Suppression.
 type ConfigError struct {
 	Msg      string
 	Critical bool
+	Code     string
+	Pointer  string
 }
 
 // Error returns a string with error message and critical flag.
@@ -24,9 +27,13 @@ func (ce ConfigError) Error() string {
 }
 
 // ConfigErrors is a structure for storing all errors in the config.
+//
+// Errors suppressed by the config itself are stored separately
+// in the Suppressed field and are not counted by Len.
 type ConfigErrors struct {
-	Config *Config
-	Errors []*ConfigError
+	Config     *Config
+	Errors     []*ConfigError
+	Suppressed []*ConfigError
 }
 
 // NewConfigErrors creates a set of config errors from passed errors.
@@ -37,7 +44,14 @@ func NewConfigErrors(errors ...*ConfigError) *ConfigErrors {
 }
 
 // Add adds a new error.
+//
+// If the error is suppressed in the config, it is added
+// to the list of suppressed errors.
 func (ce *ConfigErrors) Add(err *ConfigError) {
+	if ce.Config != nil && ce.Config.IsSuppressed(err) {
+		ce.Suppressed = append(ce.Suppressed, err)
+		return
+	}
 	ce.Errors = append(ce.Errors, err)
 }
 
@@ -47,10 +61,21 @@ func (ce *ConfigErrors) Len() int {
 }
 
 // Error returns a string with one error on each line.
+//
+// See PlainFormatter
 func (ce *ConfigErrors) Error() string {
-	var res string
-	for _, e := range ce.Errors {
-		res += fmt.Sprintf("config %s: %s\n", ce.Config.Path, e.Error())
+	return ce.Format(PlainFormatter{})
+}
+
+// Format returns a string with errors rendered by the passed formatter.
+func (ce *ConfigErrors) Format(f Formatter) string {
+	return f.Format(ce)
+}
+
+// path returns the path of the config for which the errors were found.
+func (ce *ConfigErrors) path() string {
+	if ce.Config == nil {
+		return ""
 	}
-	return res
+	return ce.Config.Path
 }
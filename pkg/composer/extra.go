@@ -0,0 +1,28 @@
+package composer
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ExtraInto decodes the value of the extra section with the passed
+// key into v, for example, the settings of a plugin or a framework.
+//
+// If the key is not present, v is not changed and nil is returned.
+func (c *Config) ExtraInto(key string, v interface{}) error {
+	raw, ok := c.Extra[key]
+	if !ok {
+		return nil
+	}
+
+	if err := json.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("extra.%s: %v", key, err)
+	}
+	return nil
+}
+
+// HasExtra returns true if the extra section contains the key.
+func (c *Config) HasExtra(key string) bool {
+	_, ok := c.Extra[key]
+	return ok
+}
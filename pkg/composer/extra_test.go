@@ -0,0 +1,35 @@
+package composer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtra(t *testing.T) {
+	config, _ := NewConfigFromData([]byte(`{
+		"version": "1.0.0",
+		"extra": {
+			"laravel": {"providers": ["App\\Provider"]},
+			"symfony": {"allow-contrib": true}
+		}
+	}`), "composer.json")
+
+	var laravel struct {
+		Providers []string `json:"providers"`
+	}
+	if err := config.ExtraInto("laravel", &laravel); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(laravel.Providers, []string{`App\Provider`}) {
+		t.Errorf("unexpected laravel providers: %v", laravel.Providers)
+	}
+
+	var symfony string
+	if err := config.ExtraInto("symfony", &symfony); err == nil {
+		t.Errorf("expected error for the value of the wrong type")
+	}
+
+	if config.HasExtra("unknown") || config.ExtraInto("unknown", &symfony) != nil {
+		t.Errorf("expected missing key to be ignored")
+	}
+}
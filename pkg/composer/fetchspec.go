@@ -0,0 +1,123 @@
+package composer
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FetchSpec describes how to fetch a locked package,
+// for reproducible builds outside of composer.
+type FetchSpec struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Dev     bool   `json:"dev"`
+	// Type is the type of the dist archive (zip, tar) or, if the
+	// package has no dist, of the source (git, hg, svn, path).
+	Type      string `json:"type"`
+	Url       string `json:"url"`
+	Reference string `json:"reference,omitempty"`
+	// Sha1 is the checksum of the dist archive, if it is in the lock.
+	Sha1 string `json:"sha1,omitempty"`
+}
+
+// FetchSpecs is the list of fetch specifications of the packages
+// of a lock, see Lock.FetchSpecs.
+type FetchSpecs []FetchSpec
+
+// FetchSpecs returns the fetch specifications of the locked packages
+// sorted by name, the dev packages are included if dev is true.
+//
+// The dist of a package is preferred over the source, since it is
+// what composer installs by default. Packages with neither are skipped.
+func (l *Lock) FetchSpecs(dev bool) FetchSpecs {
+	var specs FetchSpecs
+
+	add := func(pkg LockPackage, isDev bool) {
+		source := pkg.Dist
+		if source == nil || source.Url == "" {
+			source = pkg.Source
+		}
+		if source == nil || source.Url == "" {
+			return
+		}
+
+		specs = append(specs, FetchSpec{
+			Name:      pkg.Name,
+			Version:   pkg.Version,
+			Dev:       isDev,
+			Type:      source.Type,
+			Url:       source.Url,
+			Reference: source.Reference,
+			Sha1:      source.Shasum,
+		})
+	}
+
+	for _, pkg := range l.Packages {
+		add(pkg, false)
+	}
+	if dev {
+		for _, pkg := range l.PackagesDev {
+			add(pkg, true)
+		}
+	}
+
+	sort.Slice(specs, func(i, j int) bool {
+		return specs[i].Name < specs[j].Name
+	})
+
+	return specs
+}
+
+// JSON returns the specifications as a JSON array.
+func (s FetchSpecs) JSON() ([]byte, error) {
+	if s == nil {
+		s = FetchSpecs{}
+	}
+	return json.MarshalIndent(s, "", "  ")
+}
+
+// Nix returns the specifications as a Nix expression, a function
+// of fetchurl and fetchgit returning an attribute set of the sources
+// of the packages by name.
+//
+// The lock often has no checksum of a dist, as for the dists of GitHub,
+// in which case the hash is left empty and Nix reports the correct one
+// on the first build. Packages installed from a path are skipped.
+func (s FetchSpecs) Nix() string {
+	var b strings.Builder
+	b.WriteString("# Generated from composer.lock.\n")
+	b.WriteString("{ fetchurl, fetchgit }:\n{\n")
+
+	for _, spec := range s {
+		switch spec.Type {
+		case "zip", "tar", "xz", "gzip", "phar", "rar":
+			fmt.Fprintf(&b, "  %s = fetchurl {\n", nixString(spec.Name))
+			fmt.Fprintf(&b, "    url = %s;\n", nixString(spec.Url))
+			if spec.Sha1 != "" {
+				fmt.Fprintf(&b, "    sha1 = %s;\n", nixString(spec.Sha1))
+			} else {
+				b.WriteString("    hash = \"\";\n")
+			}
+			b.WriteString("  };\n")
+		case "git":
+			fmt.Fprintf(&b, "  %s = fetchgit {\n", nixString(spec.Name))
+			fmt.Fprintf(&b, "    url = %s;\n", nixString(spec.Url))
+			fmt.Fprintf(&b, "    rev = %s;\n", nixString(spec.Reference))
+			b.WriteString("    hash = \"\";\n")
+			b.WriteString("  };\n")
+		default:
+			fmt.Fprintf(&b, "  # %s: %s sources are not supported\n", spec.Name, spec.Type)
+		}
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// nixString returns the text as a Nix string literal.
+func nixString(text string) string {
+	text = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "${", `\${`, "\n", `\n`).Replace(text)
+	return `"` + text + `"`
+}
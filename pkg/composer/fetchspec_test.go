@@ -0,0 +1,45 @@
+package composer
+
+import (
+	"testing"
+)
+
+func TestFetchSpecs(t *testing.T) {
+	lock := &Lock{
+		Packages: []LockPackage{
+			{Name: "psr/log", Version: "3.0.0", Dist: &PackageSource{Type: "zip", Url: "https://example.com/log.zip", Reference: "fe5ea30", Shasum: "da39a3ee"}},
+			{Name: "acme/lib", Version: "dev-main", Source: &PackageSource{Type: "git", Url: "https://example.com/lib.git", Reference: "abc1234"}},
+			{Name: "acme/local", Version: "dev-main", Dist: &PackageSource{Type: "path", Url: "../local"}},
+		},
+		PackagesDev: []LockPackage{
+			{Name: "phpunit/phpunit", Version: "9.6.0", Dist: &PackageSource{Type: "zip", Url: "https://example.com/phpunit.zip"}},
+		},
+	}
+
+	specs := lock.FetchSpecs(false)
+	if len(specs) != 3 || specs[0].Name != "acme/lib" || specs[0].Type != "git" || specs[2].Sha1 != "da39a3ee" {
+		t.Fatalf("unexpected specs: %+v", specs)
+	}
+	if len(lock.FetchSpecs(true)) != 4 {
+		t.Errorf("expected the dev packages to be included")
+	}
+
+	expected := `# Generated from composer.lock.
+{ fetchurl, fetchgit }:
+{
+  "acme/lib" = fetchgit {
+    url = "https://example.com/lib.git";
+    rev = "abc1234";
+    hash = "";
+  };
+  # acme/local: path sources are not supported
+  "psr/log" = fetchurl {
+    url = "https://example.com/log.zip";
+    sha1 = "da39a3ee";
+  };
+}
+`
+	if nix := specs.Nix(); nix != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, nix)
+	}
+}
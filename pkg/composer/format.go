@@ -0,0 +1,247 @@
+package composer
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+)
+
+// Formatter renders config errors to a string.
+//
+// Several sets of errors can be passed at once, for example,
+// when several configs were checked.
+type Formatter interface {
+	Format(errors ...*ConfigErrors) string
+}
+
+// PlainFormatter renders errors in insertion order,
+// one error per line, prefixed with the config path.
+//
+// This is the format used by ConfigErrors.Error.
+type PlainFormatter struct{}
+
+// Format implements the Formatter interface.
+func (PlainFormatter) Format(errors ...*ConfigErrors) string {
+	var b strings.Builder
+	for _, ce := range errors {
+		if ce == nil {
+			continue
+		}
+		for _, e := range ce.Errors {
+			fmt.Fprintf(&b, "config %s: %s\n", ce.path(), e.Error())
+		}
+	}
+	return b.String()
+}
+
+// GroupedFormatter renders errors grouped by config path
+// with a header for each file.
+//
+// Files and errors inside each file are sorted so that the
+// output does not depend on the order in which the errors were
+// added, critical errors come first. Duplicate errors are
+// printed only once.
+type GroupedFormatter struct{}
+
+// Format implements the Formatter interface.
+func (GroupedFormatter) Format(errors ...*ConfigErrors) string {
+	groups := groupErrors(errors)
+
+	var b strings.Builder
+	for i, group := range groups {
+		if i != 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "config %s:\n", group.path)
+		for _, e := range group.errors {
+			fmt.Fprintf(&b, "  %s\n", e.Error())
+		}
+	}
+	return b.String()
+}
+
+// CompactFormatter renders all errors on a single line,
+// sorted and deduplicated like in GroupedFormatter.
+//
+// Useful for logs, where each record must be a single line.
+type CompactFormatter struct{}
+
+// Format implements the Formatter interface.
+func (CompactFormatter) Format(errors ...*ConfigErrors) string {
+	groups := groupErrors(errors)
+
+	var parts []string
+	for _, group := range groups {
+		msgs := make([]string, 0, len(group.errors))
+		for _, e := range group.errors {
+			msgs = append(msgs, e.Error())
+		}
+		parts = append(parts, fmt.Sprintf("config %s: %s", group.path, strings.Join(msgs, "; ")))
+	}
+	return strings.Join(parts, " | ")
+}
+
+// MarkdownFormatter renders errors as Markdown suitable for
+// pull request comments: a summary line, a table of errors for each
+// config and a collapsible section with suppressed errors.
+//
+// Errors are sorted and deduplicated like in GroupedFormatter.
+type MarkdownFormatter struct {
+	// Title is rendered as a header before the errors, if not empty.
+	Title string
+}
+
+// Format implements the Formatter interface.
+func (f MarkdownFormatter) Format(errors ...*ConfigErrors) string {
+	groups := groupErrors(errors)
+
+	suppressed := make(map[string][]*ConfigError)
+	for _, ce := range errors {
+		if ce != nil && len(ce.Suppressed) != 0 {
+			suppressed[ce.path()] = append(suppressed[ce.path()], ce.Suppressed...)
+		}
+	}
+
+	var critical, warnings int
+	for _, group := range groups {
+		for _, e := range group.errors {
+			if e.Critical {
+				critical++
+			} else {
+				warnings++
+			}
+		}
+	}
+
+	var b strings.Builder
+	if f.Title != "" {
+		fmt.Fprintf(&b, "### %s\n\n", f.Title)
+	}
+
+	if critical == 0 && warnings == 0 {
+		b.WriteString(":white_check_mark: No problems found.\n")
+	} else {
+		fmt.Fprintf(&b, ":x: %d critical, :warning: %d warnings\n", critical, warnings)
+	}
+
+	for _, group := range groups {
+		if len(group.errors) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n#### %s\n\n", markdownCode(group.path))
+		writeMarkdownTable(&b, group.errors)
+	}
+
+	paths := make([]string, 0, len(suppressed))
+	for path := range suppressed {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		// The summary is inside an HTML block, so only HTML is escaped.
+		fmt.Fprintf(&b, "\n<details>\n<summary>%d suppressed in <code>%s</code></summary>\n\n", len(suppressed[path]), html.EscapeString(path))
+		writeMarkdownTable(&b, suppressed[path])
+		b.WriteString("\n</details>\n")
+	}
+
+	return b.String()
+}
+
+// writeMarkdownTable writes the errors as a Markdown table.
+func writeMarkdownTable(b *strings.Builder, errors []*ConfigError) {
+	b.WriteString("| Severity | Rule | Location | Message |\n")
+	b.WriteString("|---|---|---|---|\n")
+
+	for _, e := range errors {
+		severity := ":warning: warning"
+		if e.Critical {
+			severity = ":x: critical"
+		}
+
+		fmt.Fprintf(b, "| %s | %s | %s | %s |\n",
+			severity, markdownCode(e.Code), markdownCode(e.Pointer), escapeMarkdownCell(e.Msg))
+	}
+}
+
+// markdownCode returns the text as inline code, or an empty string.
+//
+// The <code> element is used instead of backticks, so the text
+// may contain backticks, and is escaped as in escapeMarkdownCell,
+// except for mentions, which GitHub does not link in code.
+func markdownCode(text string) string {
+	if text == "" {
+		return ""
+	}
+	return "<code>" + markdownEscaper.Replace(text) + "</code>"
+}
+
+// markdownEscaper escapes the HTML and the Markdown syntax
+// of the text to be placed in a table cell.
+var markdownEscaper = strings.NewReplacer(
+	"&", "&amp;", "<", "&lt;", ">", "&gt;",
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`,
+	"|", `\|`, "\r", "", "\n", " ",
+)
+
+// escapeMarkdownCell escapes the text to be placed in a table cell.
+//
+// A zero-width space is inserted after each @, so that the
+// text does not mention users or teams on GitHub.
+func escapeMarkdownCell(text string) string {
+	return strings.ReplaceAll(markdownEscaper.Replace(text), "@", "@&#8203;")
+}
+
+// errorGroup is the set of errors for a single config.
+type errorGroup struct {
+	path   string
+	errors []*ConfigError
+}
+
+// groupErrors groups the errors by config path, sorts and
+// deduplicates them.
+//
+// Sorting is done by bytes, so the result is the same regardless
+// of the current locale.
+func groupErrors(errors []*ConfigErrors) []errorGroup {
+	byPath := make(map[string][]*ConfigError)
+	for _, ce := range errors {
+		if ce == nil {
+			continue
+		}
+		byPath[ce.path()] = append(byPath[ce.path()], ce.Errors...)
+	}
+
+	groups := make([]errorGroup, 0, len(byPath))
+	for path, errs := range byPath {
+		sort.SliceStable(errs, func(i, j int) bool {
+			if errs[i].Critical != errs[j].Critical {
+				return errs[i].Critical
+			}
+			if errs[i].Msg != errs[j].Msg {
+				return errs[i].Msg < errs[j].Msg
+			}
+			if errs[i].Code != errs[j].Code {
+				return errs[i].Code < errs[j].Code
+			}
+			return errs[i].Pointer < errs[j].Pointer
+		})
+
+		uniq := errs[:0:0]
+		for i, e := range errs {
+			if i != 0 && *e == *errs[i-1] {
+				continue
+			}
+			uniq = append(uniq, e)
+		}
+
+		groups = append(groups, errorGroup{path: path, errors: uniq})
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].path < groups[j].path
+	})
+
+	return groups
+}
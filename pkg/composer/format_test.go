@@ -0,0 +1,124 @@
+package composer
+
+import (
+	"testing"
+)
+
+func TestFormatters(t *testing.T) {
+	first := &ConfigErrors{
+		Config: &Config{Path: "/b/composer.json"},
+		Errors: []*ConfigError{
+			{Msg: "second error"},
+			{Msg: "first error"},
+			{Msg: "second error"},
+			{Msg: "broken", Critical: true},
+		},
+	}
+	second := &ConfigErrors{
+		Config: &Config{Path: "/a/composer.json"},
+		Errors: []*ConfigError{
+			{Msg: "error"},
+		},
+	}
+
+	tests := []struct {
+		Formatter Formatter
+		Expected  string
+	}{
+		{
+			Formatter: PlainFormatter{},
+			Expected: "config /b/composer.json: second error\n" +
+				"config /b/composer.json: first error\n" +
+				"config /b/composer.json: second error\n" +
+				"config /b/composer.json: <critical> broken\n" +
+				"config /a/composer.json: error\n",
+		},
+		{
+			Formatter: GroupedFormatter{},
+			Expected: "config /a/composer.json:\n" +
+				"  error\n" +
+				"\n" +
+				"config /b/composer.json:\n" +
+				"  <critical> broken\n" +
+				"  first error\n" +
+				"  second error\n",
+		},
+		{
+			Formatter: CompactFormatter{},
+			Expected: "config /a/composer.json: error | " +
+				"config /b/composer.json: <critical> broken; first error; second error",
+		},
+	}
+
+	for _, test := range tests {
+		res := test.Formatter.Format(first, second)
+		if res != test.Expected {
+			t.Errorf("%T: expected:\n%s\ngot:\n%s", test.Formatter, test.Expected, res)
+		}
+	}
+}
+
+func TestGroupErrorsDeduplicates(t *testing.T) {
+	errs := &ConfigErrors{
+		Config: &Config{Path: "composer.json"},
+		Errors: []*ConfigError{
+			{Msg: "unknown package", Code: "my-rule", Pointer: "/require/a~1a"},
+			{Msg: "unknown package", Code: "my-rule", Pointer: "/require/b~1b"},
+			{Msg: "unknown package", Code: "my-rule", Pointer: "/require/a~1a"},
+		},
+	}
+
+	groups := groupErrors([]*ConfigErrors{errs})
+	if len(groups) != 1 || len(groups[0].errors) != 2 {
+		t.Fatalf("expected 2 unique errors, got %v", groups)
+	}
+	if groups[0].errors[0].Pointer != "/require/a~1a" || groups[0].errors[1].Pointer != "/require/b~1b" {
+		t.Errorf("expected errors to be sorted by pointer, got %v", groups[0].errors)
+	}
+}
+
+func TestMarkdownFormatter(t *testing.T) {
+	errs := &ConfigErrors{
+		Config: &Config{Path: "/a/composer.json"},
+		Errors: []*ConfigError{
+			{Msg: "a | b", Code: "my-rule", Pointer: "/name"},
+			{Msg: "ping @team about <b>`x`</b> & *y*", Code: "my_rule", Pointer: "/require/a|b"},
+			{Msg: "broken", Critical: true},
+		},
+		Suppressed: []*ConfigError{
+			{Msg: "ignored", Code: "other-rule"},
+		},
+	}
+
+	expected := "### Config check\n" +
+		"\n" +
+		":x: 1 critical, :warning: 2 warnings\n" +
+		"\n" +
+		"#### <code>/a/composer.json</code>\n" +
+		"\n" +
+		"| Severity | Rule | Location | Message |\n" +
+		"|---|---|---|---|\n" +
+		"| :x: critical |  |  | broken |\n" +
+		"| :warning: warning | <code>my-rule</code> | <code>/name</code> | a \\| b |\n" +
+		"| :warning: warning | <code>my\\_rule</code> | <code>/require/a\\|b</code> | " +
+		"ping @&#8203;team about &lt;b&gt;\\`x\\`&lt;/b&gt; &amp; \\*y\\* |\n" +
+		"\n" +
+		"<details>\n" +
+		"<summary>1 suppressed in <code>/a/composer.json</code></summary>\n" +
+		"\n" +
+		"| Severity | Rule | Location | Message |\n" +
+		"|---|---|---|---|\n" +
+		"| :warning: warning | <code>other-rule</code> |  | ignored |\n" +
+		"\n" +
+		"</details>\n"
+
+	res := MarkdownFormatter{Title: "Config check"}.Format(errs)
+	if res != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, res)
+	}
+
+	res = MarkdownFormatter{}.Format(nil)
+	if res != ":white_check_mark: No problems found.\n" {
+		t.Errorf("unexpected output for no errors:\n%s", res)
+	}
+}
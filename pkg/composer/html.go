@@ -0,0 +1,236 @@
+package composer
+
+import (
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"sort"
+	"strings"
+)
+
+// HTMLFormatter renders errors as a self-contained HTML page
+// with inline styles, suitable for archiving as a CI artifact.
+//
+// Errors are sorted and deduplicated like in GroupedFormatter.
+//
+// If the lock is set, the report includes the dependency graph:
+// the locked packages with their origins, see Config.PackageOrigins,
+// and the packages they depend on, see LockGraph.
+//
+// See WriteReport
+type HTMLFormatter struct {
+	// Title is the title of the page, "composer.json report" if empty.
+	Title string
+	// Lock is the lock of the first config, the dependency graph
+	// is not included if it is nil.
+	Lock *Lock
+}
+
+// htmlReport is the data of htmlTemplate.
+type htmlReport struct {
+	Title      string
+	Critical   int
+	Warnings   int
+	Groups     []htmlGroup
+	Suppressed int
+	Packages   []htmlPackage
+}
+
+type htmlGroup struct {
+	Path       string
+	Errors     []*ConfigError
+	Suppressed []*ConfigError
+}
+
+type htmlPackage struct {
+	Name         string
+	Version      string
+	Origin       string
+	Dependencies []string
+}
+
+// htmlAnchor returns the id of the row of the package in the report.
+func htmlAnchor(name string) string {
+	// A tilde cannot appear in package names and
+	// is not escaped in URLs, unlike a slash.
+	return "package-" + strings.ReplaceAll(name, "/", "~")
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{"anchor": htmlAnchor}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #24292e; }
+h1 { font-size: 1.6em; }
+h2 { font-size: 1.1em; font-family: monospace; margin-top: 2em; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border: 1px solid #d1d5da; padding: 6px 10px; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+code { font-size: 0.9em; }
+.critical { color: #cb2431; font-weight: bold; }
+.warning { color: #b08800; }
+.ok { color: #22863a; }
+.summary { font-size: 1.1em; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{if or .Critical .Warnings}}
+<p class="summary"><span class="critical">{{.Critical}} critical</span>, <span class="warning">{{.Warnings}} warnings</span>{{if .Suppressed}}, {{.Suppressed}} suppressed{{end}}</p>
+{{else}}
+<p class="summary ok">No problems found.{{if .Suppressed}} {{.Suppressed}} suppressed.{{end}}</p>
+{{end}}
+{{range .Groups}}
+<h2>{{.Path}}</h2>
+{{if .Errors}}{{template "table" .Errors}}{{end}}
+{{if .Suppressed}}
+<details>
+<summary>{{len .Suppressed}} suppressed</summary>
+{{template "table" .Suppressed}}
+</details>
+{{end}}
+{{end}}
+{{if .Packages}}
+<h2>Dependencies</h2>
+<table>
+<tr><th>Package</th><th>Version</th><th>Origin</th><th>Depends on</th></tr>
+{{range .Packages}}<tr>
+<td id="{{anchor .Name}}"><code>{{.Name}}</code></td>
+<td>{{.Version}}</td>
+<td>{{.Origin}}</td>
+<td>{{range $i, $dep := .Dependencies}}{{if $i}}, {{end}}<a href="#{{anchor $dep}}"><code>{{$dep}}</code></a>{{end}}</td>
+</tr>
+{{end}}</table>
+{{end}}
+</body>
+</html>
+{{define "table"}}<table>
+<tr><th>Severity</th><th>Rule</th><th>Location</th><th>Message</th></tr>
+{{range .}}<tr>
+<td>{{if .Critical}}<span class="critical">critical</span>{{else}}<span class="warning">warning</span>{{end}}</td>
+<td>{{if .Code}}<code>{{.Code}}</code>{{end}}</td>
+<td>{{if .Pointer}}<code>{{.Pointer}}</code>{{end}}</td>
+<td>{{.Msg}}</td>
+</tr>
+{{end}}</table>{{end}}`))
+
+// Format implements the Formatter interface.
+//
+// If the report cannot be rendered, the error is returned as the
+// result, use WriteReport to get it as an error.
+func (f HTMLFormatter) Format(errors ...*ConfigErrors) string {
+	res, err := f.render(errors...)
+	if err != nil {
+		return fmt.Sprintf("%s: %v", f.title(), err)
+	}
+	return res
+}
+
+func (f HTMLFormatter) title() string {
+	if f.Title == "" {
+		return "composer.json report"
+	}
+	return f.Title
+}
+
+func (f HTMLFormatter) render(errors ...*ConfigErrors) (string, error) {
+	report := htmlReport{Title: f.title()}
+
+	groups := map[string]*htmlGroup{}
+	var paths []string
+	group := func(path string) *htmlGroup {
+		if g, ok := groups[path]; ok {
+			return g
+		}
+		g := &htmlGroup{Path: path}
+		groups[path] = g
+		paths = append(paths, path)
+		return g
+	}
+
+	for _, g := range groupErrors(errors) {
+		group(g.path).Errors = g.errors
+		for _, e := range g.errors {
+			if e.Critical {
+				report.Critical++
+			} else {
+				report.Warnings++
+			}
+		}
+	}
+
+	for _, ce := range errors {
+		if ce == nil || len(ce.Suppressed) == 0 {
+			continue
+		}
+		g := group(ce.path())
+		g.Suppressed = append(g.Suppressed, ce.Suppressed...)
+		report.Suppressed += len(ce.Suppressed)
+	}
+
+	for _, path := range paths {
+		report.Groups = append(report.Groups, *groups[path])
+	}
+
+	if f.Lock != nil {
+		report.Packages = htmlPackages(f.Lock, errors)
+	}
+
+	var b strings.Builder
+	if err := htmlTemplate.Execute(&b, report); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// htmlPackages returns the locked packages sorted by name with
+// their origins for the config of the first errors, if any.
+func htmlPackages(lock *Lock, errors []*ConfigErrors) []htmlPackage {
+	var origins PackageOrigins
+	if len(errors) != 0 && errors[0] != nil && errors[0].Config != nil {
+		origins = errors[0].Config.PackageOrigins(lock)
+	}
+
+	graph := NewLockGraph(lock)
+
+	var packages []htmlPackage
+	for _, pkg := range lock.AllPackages() {
+		packages = append(packages, htmlPackage{
+			Name:         pkg.Name,
+			Version:      pkg.Version,
+			Origin:       origins[pkg.Name],
+			Dependencies: graph.Dependencies(pkg.Name),
+		})
+	}
+
+	sort.Slice(packages, func(i, j int) bool {
+		return packages[i].Name < packages[j].Name
+	})
+	return packages
+}
+
+// WriteReport renders errors with the formatter and writes
+// the result to the file.
+//
+// Unlike HTMLFormatter.Format, the error of rendering
+// an HTML report is returned.
+//
+// Example:
+//
+//	err := composer.WriteReport("report.html", composer.HTMLFormatter{Lock: lock}, errs)
+func WriteReport(path string, f Formatter, errors ...*ConfigErrors) error {
+	var data string
+	if html, ok := f.(HTMLFormatter); ok {
+		var err error
+		data, err = html.render(errors...)
+		if err != nil {
+			return err
+		}
+	} else {
+		data = f.Format(errors...)
+	}
+
+	return ioutil.WriteFile(path, []byte(data), 0644)
+}
@@ -0,0 +1,100 @@
+package composer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHTMLFormatter(t *testing.T) {
+	errs := &ConfigErrors{
+		Config: &Config{Path: "/a/composer.json"},
+		Errors: []*ConfigError{
+			{Msg: "<script>alert(1)</script>", Code: "my-rule", Pointer: "/name"},
+			{Msg: "broken", Critical: true},
+		},
+		Suppressed: []*ConfigError{
+			{Msg: "ignored", Code: "other-rule"},
+		},
+	}
+
+	res := HTMLFormatter{Title: "Report"}.Format(errs)
+
+	for _, expected := range []string{
+		"<title>Report</title>",
+		"1 critical",
+		"1 warnings",
+		"1 suppressed",
+		"<h2>/a/composer.json</h2>",
+		"<code>my-rule</code>",
+		"&lt;script&gt;alert(1)&lt;/script&gt;",
+	} {
+		if !strings.Contains(res, expected) {
+			t.Errorf("expected the report to contain %q", expected)
+		}
+	}
+	if strings.Contains(res, "<script>") {
+		t.Errorf("messages must be escaped")
+	}
+}
+
+func TestHTMLReportDependencies(t *testing.T) {
+	lock, err := ParseLock([]byte(`{
+		"packages": [
+			{"name": "monolog/monolog", "version": "3.5.0", "require": {"php": ">=8.1", "psr/log": "^3.0"}},
+			{"name": "psr/log", "version": "3.0.0"}
+		],
+		"packages-dev": [
+			{"name": "phpunit/phpunit", "version": "10.5.1"}
+		]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errs := &ConfigErrors{
+		Config: &Config{
+			Path:       "/a/composer.json",
+			Require:    map[string]string{"monolog/monolog": "^3.0"},
+			RequireDev: map[string]string{"phpunit/phpunit": "^10.0"},
+		},
+	}
+
+	dir, err := ioutil.TempDir("", "composer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "report.html")
+	if err := WriteReport(path, HTMLFormatter{Lock: lock}, errs); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := string(data)
+	for _, expected := range []string{
+		"<h2>Dependencies</h2>",
+		"<td id=\"package-monolog~monolog\"><code>monolog/monolog</code></td>\n<td>3.5.0</td>\n<td>direct</td>\n" +
+			"<td><a href=\"#package-psr~log\"><code>psr/log</code></a></td>",
+		"<td id=\"package-psr~log\"><code>psr/log</code></td>\n<td>3.0.0</td>\n<td>transitive</td>",
+		"<td>direct-dev</td>",
+	} {
+		if !strings.Contains(res, expected) {
+			t.Errorf("expected the report to contain %q:\n%s", expected, res)
+		}
+	}
+
+	if res := (HTMLFormatter{}).Format(errs); strings.Contains(res, "Dependencies") {
+		t.Errorf("expected no dependencies without a lock")
+	}
+
+	if err := WriteReport(filepath.Join(dir, "missing", "report.html"), HTMLFormatter{}, errs); err == nil {
+		t.Errorf("expected an error for a missing dir")
+	}
+}
@@ -0,0 +1,189 @@
+package composer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// InferredConfig is a composer.json reconstructed
+// from the installed vendor dir, see InferConfig.
+type InferredConfig struct {
+	Require     map[string]string `json:"require,omitempty"`
+	RequireDev  map[string]string `json:"require-dev,omitempty"`
+	Autoload    InferredAutoload  `json:"autoload"`
+	AutoloadDev InferredAutoload  `json:"autoload-dev"`
+}
+
+// InferredAutoload is the autoload section of an InferredConfig.
+type InferredAutoload struct {
+	Psr4 map[string]string `json:"psr-4,omitempty"`
+}
+
+// inferredDevDirs are the top-level dirs of the project
+// whose namespaces are added to autoload-dev.
+var inferredDevDirs = map[string]bool{
+	"test":  true,
+	"tests": true,
+}
+
+// InferConfig reconstructs a plausible composer.json for the project
+// whose dependencies are installed in the vendor dir, for the projects
+// that lost or never had one.
+//
+// The installed.json does not record which packages were required
+// directly, so the packages that no other installed package requires
+// are considered direct. They are required with a caret constraint on
+// the installed minor version, or the installed branch, and the dev
+// packages go to require-dev.
+//
+// The psr-4 autoload is guessed from the classes declared in the top-level
+// dirs of the project, the parent of the vendor dir: the namespace of the
+// first class whose file follows psr-4 is mapped to the dir. The tests
+// dirs go to autoload-dev.
+func InferConfig(vendorDir string) (*InferredConfig, error) {
+	installed, err := LoadInstalled(filepath.Join(vendorDir, "composer", "installed.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	config := &InferredConfig{}
+
+	lock := &Lock{}
+	for _, pkg := range installed.Packages {
+		lock.Packages = append(lock.Packages, pkg.LockPackage)
+	}
+	graph := newLockGraph(lock)
+
+	required := map[string]bool{}
+	for _, pkg := range lock.Packages {
+		for dep := range pkg.Require {
+			if IsPlatformPackage(dep) {
+				continue
+			}
+			for _, key := range graph.resolve(dep) {
+				if !strings.EqualFold(key, pkg.Name) {
+					required[key] = true
+				}
+			}
+		}
+	}
+
+	for _, pkg := range installed.Packages {
+		if required[strings.ToLower(pkg.Name)] {
+			continue
+		}
+
+		if _, dev, _ := installed.Package(pkg.Name); dev {
+			if config.RequireDev == nil {
+				config.RequireDev = map[string]string{}
+			}
+			config.RequireDev[pkg.Name] = inferredConstraint(pkg.Version)
+			continue
+		}
+
+		if config.Require == nil {
+			config.Require = map[string]string{}
+		}
+		config.Require[pkg.Name] = inferredConstraint(pkg.Version)
+	}
+
+	rootDir := filepath.Dir(filepath.Clean(vendorDir))
+	entries, err := ioutil.ReadDir(rootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() || strings.HasPrefix(name, ".") || filepath.Join(rootDir, name) == filepath.Clean(vendorDir) {
+			continue
+		}
+
+		prefix, err := inferPsr4Prefix(filepath.Join(rootDir, name))
+		if err != nil {
+			return nil, err
+		}
+		if prefix == "" {
+			continue
+		}
+
+		autoload := &config.Autoload
+		if inferredDevDirs[strings.ToLower(name)] {
+			autoload = &config.AutoloadDev
+		}
+		if _, ok := autoload.Psr4[prefix]; ok {
+			continue
+		}
+		if autoload.Psr4 == nil {
+			autoload.Psr4 = map[string]string{}
+		}
+		autoload.Psr4[prefix] = name + "/"
+	}
+
+	return config, nil
+}
+
+// inferredConstraint returns the constraint
+// on the installed version of a package.
+func inferredConstraint(raw string) string {
+	ver, err := parsePlatformVersion(raw)
+	if err != nil {
+		// Branches, such as dev-main, are required as is.
+		return raw
+	}
+	return fmt.Sprintf("^%d.%d", ver.Major, ver.Minor)
+}
+
+// errPrefixFound stops the walk of inferPsr4Prefix.
+var errPrefixFound = fmt.Errorf("prefix found")
+
+// inferPsr4Prefix returns the psr-4 prefix of the first class in the
+// dir whose namespace ends with the path of its file relative to the dir,
+// or an empty string if there is no such class in the global namespace.
+func inferPsr4Prefix(dir string) (string, error) {
+	var prefix string
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || filepath.Ext(path) != ".php" {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		class := strings.TrimSuffix(filepath.ToSlash(rel), ".php")
+		suffix := `\` + strings.ReplaceAll(class, "/", `\`)
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		for _, name := range FindClasses(string(data)) {
+			if !strings.HasSuffix(`\`+name, suffix) || len(name) < len(suffix) {
+				continue
+			}
+			prefix = name[:len(name)-len(suffix)+1]
+			return errPrefixFound
+		}
+		return nil
+	})
+	if err != nil && err != errPrefixFound {
+		return "", err
+	}
+
+	return prefix, nil
+}
+
+// JSON returns the config as composer.json contents.
+func (c *InferredConfig) JSON() ([]byte, error) {
+	return json.MarshalIndent(c, "", "    ")
+}
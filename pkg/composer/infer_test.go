@@ -0,0 +1,85 @@
+package composer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestInferConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "composer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"vendor/composer/installed.json": `{
+			"packages": [
+				{"name": "monolog/monolog", "version": "3.5.0", "require": {"php": ">=8.1", "psr/log-implementation": "^3.0"}},
+				{"name": "my/logger", "version": "0.4.2", "provide": {"psr/log-implementation": "3.0.0"}},
+				{"name": "acme/tools", "version": "dev-main"},
+				{"name": "phpunit/phpunit", "version": "10.5.1"}
+			],
+			"dev": true,
+			"dev-package-names": ["phpunit/phpunit"]
+		}`,
+		"src/Kernel.php":              `<?php namespace App; class Kernel {}`,
+		"src/Http/Controller.php":     `<?php namespace App\Http; abstract class Controller {}`,
+		"lib/helpers.php":             `<?php function helper() {}`,
+		"tests/Unit/KernelTest.php":   `<?php namespace App\Tests\Unit; class KernelTest {}`,
+		"vendor/acme/tools/Tool.php":  `<?php namespace Acme; class Tool {}`,
+		".github/scripts/Release.php": `<?php class Release {}`,
+	}
+	for file, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config, err := InferConfig(filepath.Join(dir, "vendor"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &InferredConfig{
+		Require: map[string]string{
+			"monolog/monolog": "^3.5",
+			"acme/tools":      "dev-main",
+		},
+		RequireDev: map[string]string{
+			"phpunit/phpunit": "^10.5",
+		},
+		Autoload: InferredAutoload{
+			Psr4: map[string]string{`App\`: "src/"},
+		},
+		AutoloadDev: InferredAutoload{
+			Psr4: map[string]string{`App\Tests\`: "tests/"},
+		},
+	}
+	if !reflect.DeepEqual(config, expected) {
+		t.Errorf("unexpected config:\n%+v\nexpected:\n%+v", config, expected)
+	}
+
+	data, err := config.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, errs := NewConfigFromData(data, filepath.Join(dir, "composer.json"))
+	if errs != nil && hasCriticalError(errs) {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if parsed.Require["monolog/monolog"] != "^3.5" {
+		t.Errorf("unexpected require: %v", parsed.Require)
+	}
+
+	if _, err := InferConfig(filepath.Join(dir, "missing")); err == nil {
+		t.Errorf("expected an error for a missing vendor dir")
+	}
+}
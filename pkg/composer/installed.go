@@ -0,0 +1,98 @@
+package composer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// Installed is the vendor/composer/installed.json file,
+// which lists the packages installed in the vendor dir.
+//
+// Both formats are read: composer 2 wraps the packages in an object
+// with the dev mode, composer 1 writes the bare list of packages.
+type Installed struct {
+	Packages []InstalledPackage `json:"packages"`
+	// Dev is true if the dev packages were installed.
+	// Composer 1 does not record it, so it is always false.
+	Dev bool `json:"dev"`
+	// DevPackageNames are the names of the packages installed only
+	// in development. Composer 1 does not record them.
+	DevPackageNames []string `json:"dev-package-names"`
+	// Legacy is true if the file was written by composer 1.
+	Legacy bool `json:"-"`
+}
+
+// InstalledPackage is a package listed in installed.json.
+type InstalledPackage struct {
+	LockPackage
+	// InstallPath is the path to the package relative to the
+	// vendor/composer dir. It is empty in composer 1.
+	InstallPath string `json:"install-path"`
+}
+
+// LoadInstalled reads the list of installed packages from the file.
+//
+// See Config.InstalledPath
+func LoadInstalled(filename string) (*Installed, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	installed, err := ParseInstalled(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	return installed, nil
+}
+
+// ParseInstalled parses the contents of installed.json,
+// the format is detected automatically.
+func ParseInstalled(data []byte) (*Installed, error) {
+	var installed Installed
+
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		if err := json.Unmarshal(data, &installed.Packages); err != nil {
+			return nil, err
+		}
+		installed.Legacy = true
+	} else if err := json.Unmarshal(data, &installed); err != nil {
+		return nil, err
+	}
+
+	for _, pkg := range installed.Packages {
+		if pkg.Name == "" {
+			return nil, fmt.Errorf("installed package without a name")
+		}
+	}
+
+	return &installed, nil
+}
+
+// InstalledPath returns the path to the installed.json of the config.
+func (c *Config) InstalledPath() string {
+	return filepath.Join(c.VendorDir(), "composer", "installed.json")
+}
+
+// Package returns the installed package with the passed name,
+// dev is true if the package is installed only in development.
+//
+// As in composer, package names are case-insensitive.
+func (i *Installed) Package(name string) (pkg *InstalledPackage, dev bool, ok bool) {
+	for idx := range i.Packages {
+		if !strings.EqualFold(i.Packages[idx].Name, name) {
+			continue
+		}
+		for _, devName := range i.DevPackageNames {
+			if strings.EqualFold(devName, name) {
+				dev = true
+			}
+		}
+		return &i.Packages[idx], dev, true
+	}
+	return nil, false, false
+}
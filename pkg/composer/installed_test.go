@@ -0,0 +1,62 @@
+package composer
+
+import (
+	"testing"
+)
+
+func TestLegacyFormats(t *testing.T) {
+	lock, err := ParseLock([]byte(`{
+		"hash": "abc",
+		"packages": [{"name": "psr/log", "version": "1.1.4"}],
+		"packages-dev": null,
+		"platform": []
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !lock.IsLegacy() || lock.ContentHash != "abc" || len(lock.PackagesDev) != 0 {
+		t.Errorf("unexpected composer 1 lock: %+v", lock)
+	}
+
+	for api, legacy := range map[string]bool{"1.1.0": true, "2.0.0": false, "2.6.0": false} {
+		if (&Lock{PluginApiVersion: api}).IsLegacy() != legacy {
+			t.Errorf("expected IsLegacy to be %v for plugin-api-version %s", legacy, api)
+		}
+	}
+
+	installed, err := ParseInstalled([]byte(`[
+		{"name": "psr/log", "version": "1.1.4", "installation-source": "dist"}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !installed.Legacy || len(installed.Packages) != 1 || installed.Packages[0].Name != "psr/log" {
+		t.Errorf("unexpected composer 1 installed.json: %+v", installed)
+	}
+
+	installed, err = ParseInstalled([]byte(`{
+		"packages": [
+			{"name": "psr/log", "version": "3.0.0", "install-path": "../psr/log"},
+			{"name": "phpunit/phpunit", "version": "9.6.0", "install-path": "../phpunit/phpunit"}
+		],
+		"dev": true,
+		"dev-package-names": ["phpunit/phpunit"]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if installed.Legacy || !installed.Dev {
+		t.Errorf("unexpected composer 2 installed.json: %+v", installed)
+	}
+	pkg, dev, ok := installed.Package("PHPUnit/PHPUnit")
+	if !ok || !dev || pkg.InstallPath != "../phpunit/phpunit" {
+		t.Errorf("unexpected phpunit/phpunit: %+v", pkg)
+	}
+	if _, dev, ok := installed.Package("psr/log"); !ok || dev {
+		t.Errorf("expected psr/log to be a production package")
+	}
+
+	if _, err := ParseInstalled([]byte(`[{"version": "1.0.0"}]`)); err == nil {
+		t.Errorf("expected an error for a package without a name")
+	}
+}
@@ -0,0 +1,305 @@
+package composer
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LoadInstalledPhp reads the list of installed packages from
+// the vendor/composer/installed.php file written by composer 2.
+//
+// See Config.InstalledPhpPath
+func LoadInstalledPhp(filename string) (*Installed, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	installed, err := ParseInstalledPhp(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	return installed, nil
+}
+
+// ParseInstalledPhp parses the contents of installed.php,
+// the PHP array that composer 2 writes for the InstalledVersions class.
+//
+// The file has less data than installed.json: only the name, version,
+// type, install path and dev flag of the packages are filled in. The
+// install paths are relative to the vendor/composer dir, as in
+// installed.json, and the packages that are only provided or replaced
+// by other packages, and so have no install path, are skipped.
+func ParseInstalledPhp(data []byte) (*Installed, error) {
+	code := strings.TrimSpace(string(data))
+	code = strings.TrimPrefix(code, "<?php")
+
+	p := &phpArrayParser{code: code}
+	p.skipSpace()
+	if !p.consumeWord("return") {
+		return nil, fmt.Errorf("expected a return statement")
+	}
+
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+
+	root, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected an array")
+	}
+	versions, ok := root["versions"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected an array of versions")
+	}
+
+	installed := &Installed{}
+	if rootPackage, ok := root["root"].(map[string]interface{}); ok {
+		installed.Dev, _ = rootPackage["dev"].(bool)
+	}
+
+	for name, value := range versions {
+		entry, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected an array for the package %s", name)
+		}
+
+		installPath, ok := entry["install_path"].(string)
+		if !ok {
+			continue
+		}
+
+		pkg := InstalledPackage{InstallPath: path.Clean(installPath)}
+		pkg.Name = name
+		pkg.Version, _ = entry["pretty_version"].(string)
+		pkg.Type, _ = entry["type"].(string)
+		installed.Packages = append(installed.Packages, pkg)
+
+		if dev, _ := entry["dev_requirement"].(bool); dev {
+			installed.DevPackageNames = append(installed.DevPackageNames, name)
+		}
+	}
+
+	sort.Slice(installed.Packages, func(i, j int) bool {
+		return installed.Packages[i].Name < installed.Packages[j].Name
+	})
+	sort.Strings(installed.DevPackageNames)
+
+	return installed, nil
+}
+
+// InstalledPhpPath returns the path to the installed.php of the config.
+func (c *Config) InstalledPhpPath() string {
+	return filepath.Join(c.VendorDir(), "composer", "installed.php")
+}
+
+// LoadInstalled reads the installed packages of the config from
+// installed.json or, if it does not exist, as in some optimized
+// deployments, from installed.php.
+func (c *Config) LoadInstalled() (*Installed, error) {
+	installed, err := LoadInstalled(c.InstalledPath())
+	if os.IsNotExist(err) {
+		return LoadInstalledPhp(c.InstalledPhpPath())
+	}
+	return installed, err
+}
+
+// PackageForPath returns the installed package the file or dir with the
+// passed path belongs to, the relative paths are relative to the vendor dir.
+//
+// If packages are installed inside each other, the innermost one wins.
+func (i *Installed) PackageForPath(vendorDir string, path string) (*InstalledPackage, bool) {
+	composerDir := filepath.Join(vendorDir, "composer")
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(vendorDir, path)
+	}
+	path = filepath.Clean(path)
+
+	var found *InstalledPackage
+	var foundDir string
+	for idx := range i.Packages {
+		pkg := &i.Packages[idx]
+		if pkg.InstallPath == "" {
+			continue
+		}
+
+		dir := filepath.FromSlash(pkg.InstallPath)
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(composerDir, dir)
+		}
+		dir = filepath.Clean(dir)
+
+		if path != dir && !strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			continue
+		}
+		if found == nil || len(dir) > len(foundDir) {
+			found, foundDir = pkg, dir
+		}
+	}
+
+	return found, found != nil
+}
+
+// phpArrayParser parses the PHP array literals written by composer's
+// var_export, with the __DIR__ constant concatenated with strings.
+//
+// Arrays are parsed into maps, the keys of the lists are
+// converted to strings, the scalars into strings, integers,
+// booleans and nil.
+type phpArrayParser struct {
+	code string
+	pos  int
+}
+
+func (p *phpArrayParser) parseValue() (interface{}, error) {
+	value, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		p.skipSpace()
+		if !p.consume(".") {
+			return value, nil
+		}
+
+		next, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		value = fmt.Sprint(value) + fmt.Sprint(next)
+	}
+}
+
+func (p *phpArrayParser) parseOperand() (interface{}, error) {
+	p.skipSpace()
+	switch {
+	case p.consumeWord("array"):
+		p.skipSpace()
+		if !p.consume("(") {
+			return nil, p.errorf("expected (")
+		}
+		return p.parseArray(")")
+	case p.consume("["):
+		return p.parseArray("]")
+	case p.consumeWord("__DIR__"):
+		// The paths are made relative to the dir of the file.
+		return ".", nil
+	case p.consumeWord("true"):
+		return true, nil
+	case p.consumeWord("false"):
+		return false, nil
+	case p.consumeWord("null"):
+		return nil, nil
+	case p.pos < len(p.code) && p.code[p.pos] == '\'':
+		return p.parseString()
+	}
+
+	start := p.pos
+	if p.pos < len(p.code) && p.code[p.pos] == '-' {
+		p.pos++
+	}
+	for p.pos < len(p.code) && p.code[p.pos] >= '0' && p.code[p.pos] <= '9' {
+		p.pos++
+	}
+	number, err := strconv.ParseInt(p.code[start:p.pos], 10, 64)
+	if err != nil {
+		p.pos = start
+		return nil, p.errorf("unexpected value")
+	}
+	return number, nil
+}
+
+func (p *phpArrayParser) parseArray(end string) (interface{}, error) {
+	array := map[string]interface{}{}
+	var next int64
+
+	for {
+		p.skipSpace()
+		if p.consume(end) {
+			return array, nil
+		}
+
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+
+		p.skipSpace()
+		key := strconv.FormatInt(next, 10)
+		if p.consume("=>") {
+			key = fmt.Sprint(value)
+			if index, ok := value.(int64); ok && index >= next {
+				next = index
+			}
+			value, err = p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+		}
+		next++
+		array[key] = value
+
+		p.skipSpace()
+		if !p.consume(",") && !strings.HasPrefix(p.code[p.pos:], end) {
+			return nil, p.errorf("expected , or %s", end)
+		}
+	}
+}
+
+// parseString parses a single-quoted string, in which
+// only \' and \\ are escape sequences.
+func (p *phpArrayParser) parseString() (string, error) {
+	var b strings.Builder
+	for i := p.pos + 1; i < len(p.code); i++ {
+		switch ch := p.code[i]; {
+		case ch == '\'':
+			p.pos = i + 1
+			return b.String(), nil
+		case ch == '\\' && i+1 < len(p.code) && (p.code[i+1] == '\'' || p.code[i+1] == '\\'):
+			i++
+			b.WriteByte(p.code[i])
+		default:
+			b.WriteByte(ch)
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+func (p *phpArrayParser) skipSpace() {
+	for p.pos < len(p.code) && strings.IndexByte(" \t\r\n", p.code[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+func (p *phpArrayParser) consume(token string) bool {
+	if !strings.HasPrefix(p.code[p.pos:], token) {
+		return false
+	}
+	p.pos += len(token)
+	return true
+}
+
+// consumeWord consumes the case-insensitive keyword or constant.
+func (p *phpArrayParser) consumeWord(word string) bool {
+	end := p.pos + len(word)
+	if end > len(p.code) || !strings.EqualFold(p.code[p.pos:end], word) {
+		return false
+	}
+	if end < len(p.code) && isIdentChar(p.code[end]) {
+		return false
+	}
+	p.pos = end
+	return true
+}
+
+func (p *phpArrayParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("offset %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
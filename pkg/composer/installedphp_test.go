@@ -0,0 +1,135 @@
+package composer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const testInstalledPhp = `<?php return array(
+    'root' => array(
+        'name' => '__root__',
+        'pretty_version' => 'dev-main',
+        'version' => 'dev-main',
+        'reference' => NULL,
+        'type' => 'library',
+        'install_path' => __DIR__ . '/../../',
+        'aliases' => array(),
+        'dev' => true,
+    ),
+    'versions' => array(
+        'psr/log' => array(
+            'pretty_version' => '3.0.0',
+            'version' => '3.0.0.0',
+            'reference' => 'fe5ea303b0887d5caefd3d431c3e61ad47037001',
+            'type' => 'library',
+            'install_path' => __DIR__ . '/../psr/log',
+            'aliases' => array(),
+            'dev_requirement' => false,
+        ),
+        'psr/log-implementation' => array(
+            'dev_requirement' => false,
+            'provided' => array(
+                0 => '3.0.0',
+            ),
+        ),
+        'phpunit/phpunit' => array(
+            'pretty_version' => '10.5.1',
+            'version' => '10.5.1.0',
+            'reference' => 'd5d9c2d6b7e0f7ea1e6a2c4ce58e4d0b3d5c01a4',
+            'type' => 'library',
+            'install_path' => __DIR__ . '/../phpunit/phpunit',
+            'aliases' => array(),
+            'dev_requirement' => true,
+        ),
+        'my/plugin' => array(
+            'pretty_version' => '1.0.0',
+            'version' => '1.0.0.0',
+            'reference' => 'It\'s a \\ reference',
+            'type' => 'composer-plugin',
+            'install_path' => __DIR__ . '/../my/plugin' . '/lib',
+            'aliases' => [],
+            'dev_requirement' => false,
+        ),
+    ),
+);
+`
+
+func TestParseInstalledPhp(t *testing.T) {
+	installed, err := ParseInstalledPhp([]byte(testInstalledPhp))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var paths []string
+	for _, pkg := range installed.Packages {
+		paths = append(paths, pkg.Name+" "+pkg.Version+" "+pkg.InstallPath)
+	}
+	expected := []string{
+		"my/plugin 1.0.0 ../my/plugin/lib",
+		"phpunit/phpunit 10.5.1 ../phpunit/phpunit",
+		"psr/log 3.0.0 ../psr/log",
+	}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("unexpected packages: %v", paths)
+	}
+	if !installed.Dev || !reflect.DeepEqual(installed.DevPackageNames, []string{"phpunit/phpunit"}) {
+		t.Errorf("unexpected dev packages: %v %v", installed.Dev, installed.DevPackageNames)
+	}
+
+	if pkg, ok := installed.PackageForPath("vendor", "psr/log/src/LoggerInterface.php"); !ok || pkg.Name != "psr/log" {
+		t.Errorf("expected psr/log for its file, got %v", pkg)
+	}
+	if _, ok := installed.PackageForPath("vendor", "psr/logger/src/Logger.php"); ok {
+		t.Errorf("expected no package for a sibling dir")
+	}
+
+	for _, data := range []string{
+		`<?php return array('versions' => array('a/b' => array('install_path' => __DIR__ . )));`,
+		`<?php return array('versions' => array('a/b' => 'broken'));`,
+		`<?php echo 1;`,
+		`<?php return array('root' => array());`,
+	} {
+		if _, err := ParseInstalledPhp([]byte(data)); err == nil {
+			t.Errorf("expected an error for %s", data)
+		}
+	}
+}
+
+func TestLoadInstalledFallback(t *testing.T) {
+	dir, err := ioutil.TempDir("", "composer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	config := &Config{RootDir: dir}
+	if err := os.MkdirAll(filepath.Join(dir, "vendor", "composer"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(config.InstalledPhpPath(), []byte(testInstalledPhp), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	installed, err := config.LoadInstalled()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, ok := installed.Package("psr/log"); !ok {
+		t.Errorf("expected the packages of installed.php, got %+v", installed)
+	}
+
+	installedJson := `{"packages": [{"name": "monolog/monolog", "version": "3.5.0", "install-path": "../monolog/monolog"}]}`
+	if err := ioutil.WriteFile(config.InstalledPath(), []byte(installedJson), 0644); err != nil {
+		t.Fatal(err)
+	}
+	installed, err = config.LoadInstalled()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, ok := installed.Package("monolog/monolog"); !ok {
+		t.Errorf("expected installed.json to be preferred, got %+v", installed)
+	}
+}
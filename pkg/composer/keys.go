@@ -0,0 +1,276 @@
+package composer
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// CodeSuspiciousKey is the code of the errors reported for keys
+// that composer ignores or rejects: misspelled keys, keys at the
+// wrong level and values of the wrong shape.
+const CodeSuspiciousKey = "suspicious-key"
+
+// topLevelKeys are the keys of composer.json.
+var topLevelKeys = []string{
+	"name", "description", "version", "type", "keywords", "homepage", "readme",
+	"time", "license", "authors", "support", "funding", "require", "require-dev",
+	"conflict", "replace", "provide", "suggest", "autoload", "autoload-dev",
+	"include-path", "target-dir", "minimum-stability", "prefer-stable",
+	"repositories", "config", "scripts", "scripts-descriptions", "scripts-aliases",
+	"extra", "bin", "archive", "abandoned", "non-feature-branches", "php-ext",
+	"_comment",
+}
+
+// autoloadKeys are the keys of the autoload and autoload-dev sections.
+var autoloadKeys = []string{"psr-0", "psr-4", "classmap", "files", "exclude-from-classmap"}
+
+// authConfigKeys are the keys of the config section that
+// are not stored in ComposerConfig.
+var authConfigKeys = []string{
+	"github-oauth", "gitlab-oauth", "gitlab-token", "bitbucket-oauth",
+	"http-basic", "bearer", "audit", "gitlab-protocol", "bump-after-update",
+	"allow-missing-requirements", "update-with-minimal-changes",
+}
+
+// keyAliases are the common misspellings that are too
+// far from the correct key to be found by the distance.
+var keyAliases = map[string]string{
+	"dev-require":  "require-dev",
+	"requiredev":   "require-dev",
+	"repository":   "repositories",
+	"repos":        "repositories",
+	"dev-autoload": "autoload-dev",
+	"psr4":         "psr-4",
+	"psr0":         "psr-0",
+	"class-map":    "classmap",
+}
+
+// configKeys returns the keys of the config section.
+func configKeys() []string {
+	keys := append([]string{}, authConfigKeys...)
+
+	typ := reflect.TypeOf(ComposerConfig{})
+	for i := 0; i < typ.NumField(); i++ {
+		tag := strings.Split(typ.Field(i).Tag.Get("json"), ",")[0]
+		if tag != "" && tag != "-" {
+			keys = append(keys, tag)
+		}
+	}
+	return keys
+}
+
+// checkKeys reports the misspelled keys, the keys at the wrong
+// level and the autoload lists given as a string.
+//
+// Unlike the decoding, it works on the raw data, since such keys
+// are silently dropped by encoding/json.
+func checkKeys(data []byte) []*ConfigError {
+	var root map[string]json.RawMessage
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil
+	}
+
+	var errors []*ConfigError
+	report := func(pointer string, format string, args ...interface{}) {
+		errors = append(errors, &ConfigError{
+			Msg:      fmt.Sprintf(format, args...),
+			Critical: false,
+			Code:     CodeSuspiciousKey,
+			Pointer:  pointer,
+		})
+	}
+
+	cfgKeys := configKeys()
+
+	for _, key := range sortedRawKeys(root) {
+		pointer := "/" + escapePointer(key)
+		switch {
+		case containsString(topLevelKeys, key):
+		case containsString(autoloadKeys, key):
+			report(pointer, "key '%s' must be inside autoload or autoload-dev", key)
+		case containsString(cfgKeys, key):
+			report(pointer, "key '%s' must be inside config", key)
+		default:
+			if suggestion := suggestKey(key, topLevelKeys); suggestion != "" {
+				report(pointer, "unknown key '%s', did you mean '%s'?", key, suggestion)
+			}
+		}
+	}
+
+	for _, section := range []string{"autoload", "autoload-dev"} {
+		var autoload map[string]json.RawMessage
+		if err := json.Unmarshal(root[section], &autoload); err != nil {
+			continue
+		}
+
+		for _, key := range sortedRawKeys(autoload) {
+			pointer := "/" + section + "/" + escapePointer(key)
+			if !containsString(autoloadKeys, key) {
+				if suggestion := suggestKey(key, autoloadKeys); suggestion != "" {
+					report(pointer, "unknown key '%s' in %s, did you mean '%s'?", key, section, suggestion)
+				}
+				continue
+			}
+
+			var single string
+			if key != "psr-0" && key != "psr-4" && json.Unmarshal(autoload[key], &single) == nil {
+				report(pointer, `%s.%s must be an array, use ["%s"]`, section, key, single)
+			}
+		}
+	}
+
+	var config map[string]json.RawMessage
+	if err := json.Unmarshal(root["config"], &config); err == nil {
+		for _, key := range sortedRawKeys(config) {
+			if containsString(cfgKeys, key) {
+				continue
+			}
+			if suggestion := suggestKey(key, cfgKeys); suggestion != "" {
+				report("/config/"+escapePointer(key), "unknown key '%s' in config, did you mean '%s'?", key, suggestion)
+			}
+		}
+	}
+
+	return errors
+}
+
+// CodeUnsupportedKey is the code of the errors reported for keys
+// that are not supported by the composer version set with
+// LoadOptions.SchemaVersion.
+const CodeUnsupportedKey = "unsupported-key"
+
+// keySince are the composer versions in which the keys were
+// added to the schema, the keys are given as JSON pointers.
+var keySince = map[string]string{
+	"/funding":               "1.10",
+	"/scripts-aliases":       "2.5",
+	"/php-ext":               "2.8",
+	"/config/platform-check": "2.0",
+	"/config/lock":           "2.1",
+	"/config/allow-plugins":  "2.2",
+	"/config/audit":          "2.4",
+}
+
+// checkSchemaKeys reports the keys that were added to the schema
+// after the passed composer version, such keys are silently
+// ignored by that version of composer.
+//
+// An invalid version is treated as the latest one.
+func checkSchemaKeys(data []byte, schemaVersion string) []*ConfigError {
+	target, err := parsePlatformVersion(schemaVersion)
+	if err != nil {
+		return nil
+	}
+
+	var root map[string]json.RawMessage
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil
+	}
+	var config map[string]json.RawMessage
+	_ = json.Unmarshal(root["config"], &config)
+
+	var pointers []string
+	for key := range root {
+		pointers = append(pointers, "/"+escapePointer(key))
+	}
+	for key := range config {
+		pointers = append(pointers, "/config/"+escapePointer(key))
+	}
+	sort.Strings(pointers)
+
+	var errors []*ConfigError
+	for _, pointer := range pointers {
+		since, ok := keySince[pointer]
+		if !ok {
+			continue
+		}
+
+		sinceVersion, err := parsePlatformVersion(since)
+		if err != nil || target.Compare(sinceVersion) >= 0 {
+			continue
+		}
+
+		errors = append(errors, &ConfigError{
+			Msg: fmt.Sprintf("key '%s' is supported since composer %s and is ignored by composer %s",
+				strings.ReplaceAll(strings.TrimPrefix(pointer, "/"), "/", "."), since, schemaVersion),
+			Critical: false,
+			Code:     CodeUnsupportedKey,
+			Pointer:  pointer,
+		})
+	}
+
+	return errors
+}
+
+// suggestKey returns the known key the passed key is likely
+// a misspelling of, an empty string if there is none.
+func suggestKey(key string, known []string) string {
+	normalized := strings.ToLower(strings.ReplaceAll(key, "_", "-"))
+	if alias, ok := keyAliases[normalized]; ok && containsString(known, alias) {
+		return alias
+	}
+
+	best, bestDistance := "", 3
+	for _, candidate := range known {
+		if len(candidate) < 4 {
+			continue
+		}
+		if distance := levenshtein(normalized, candidate); distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best
+}
+
+// levenshtein returns the edit distance between the strings.
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func minInt(vals ...int) int {
+	res := vals[0]
+	for _, val := range vals[1:] {
+		if val < res {
+			res = val
+		}
+	}
+	return res
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedRawKeys(m map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
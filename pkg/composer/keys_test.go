@@ -0,0 +1,90 @@
+package composer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSchemaVersion(t *testing.T) {
+	data := []byte(`{
+		"version": "1.0.0",
+		"funding": [],
+		"config": {"allow-plugins": {"foo/bar": true}, "sort-packages": true}
+	}`)
+
+	_, errs := NewConfigFromData(data, "composer.json")
+	if errs != nil {
+		t.Errorf("expected no errors under the latest schema, got %v", errs)
+	}
+
+	_, errs = NewConfigFromDataWithOptions(data, "composer.json", LoadOptions{SchemaVersion: "2.1"})
+	if errs == nil || errs.Len() != 1 || errs.Errors[0].Pointer != "/config/allow-plugins" {
+		t.Fatalf("expected a single error for config.allow-plugins, got %v", errs)
+	}
+	if errs.Errors[0].Code != CodeUnsupportedKey {
+		t.Errorf("unexpected code: %s", errs.Errors[0].Code)
+	}
+
+	data = []byte(`{
+		"version": "1.0.0",
+		"scripts": {"phpstan": "phpstan analyse"},
+		"scripts-aliases": {"phpstan": ["stan"]}
+	}`)
+	_, errs = NewConfigFromDataWithOptions(data, "composer.json", LoadOptions{SchemaVersion: "2.4"})
+	if errs == nil || errs.Len() != 1 || errs.Errors[0].Pointer != "/scripts-aliases" {
+		t.Fatalf("expected a single error for scripts-aliases, got %v", errs)
+	}
+	if errs.Errors[0].Msg != "key 'scripts-aliases' is supported since composer 2.5 and is ignored by composer 2.4" {
+		t.Errorf("unexpected message: %s", errs.Errors[0].Msg)
+	}
+
+	_, errs = NewConfigFromDataWithOptions(data, "composer.json", LoadOptions{SchemaVersion: "2.5"})
+	if errs != nil {
+		t.Errorf("expected no errors under composer 2.5, got %v", errs)
+	}
+}
+
+func TestSuspiciousKeys(t *testing.T) {
+	config, errs := NewConfigFromData([]byte(`{
+		"version": "1.0.0",
+		"requires": {"foo/bar": "^1.0"},
+		"require_dev": {},
+		"repository": [],
+		"psr-4": {},
+		"vendor-dir": "lib",
+		"my-custom-key": true,
+		"autoload": {"psr4": {}, "classmap": ["src/"]},
+		"config": {"sort-package": true}
+	}`), "composer.json")
+	if config == nil {
+		t.Fatal("expected config to be loaded")
+	}
+
+	var actual []string
+	for _, err := range errs.Errors {
+		if err.Code != CodeSuspiciousKey {
+			t.Errorf("unexpected error: %v", err)
+			continue
+		}
+		actual = append(actual, err.Pointer+" "+err.Msg)
+	}
+
+	expected := []string{
+		"/psr-4 key 'psr-4' must be inside autoload or autoload-dev",
+		"/repository unknown key 'repository', did you mean 'repositories'?",
+		"/require_dev unknown key 'require_dev', did you mean 'require-dev'?",
+		"/requires unknown key 'requires', did you mean 'require'?",
+		"/vendor-dir key 'vendor-dir' must be inside config",
+		"/autoload/psr4 unknown key 'psr4' in autoload, did you mean 'psr-4'?",
+		"/config/sort-package unknown key 'sort-package' in config, did you mean 'sort-packages'?",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("mismatch errors:\nwant: %v\nhave: %v", strings.Join(expected, "\n"), strings.Join(actual, "\n"))
+	}
+
+	_, errs = NewConfigFromData([]byte(`{"version": "1.0.0", "autoload": {"files": "src/helpers.php"}}`), "composer.json")
+	if errs.Len() != 2 || !errs.Errors[0].Critical || errs.Errors[1].Msg != `autoload.files must be an array, use ["src/helpers.php"]` {
+		t.Errorf("expected a hint for autoload.files, got %v", errs)
+	}
+}
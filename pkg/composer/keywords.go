@@ -0,0 +1,52 @@
+package composer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// CodeInvalidKeyword is the code of the errors reported by CheckKeywords.
+const CodeInvalidKeyword = "invalid-keyword"
+
+// MaxKeywordLength is the maximum length of a keyword
+// in characters accepted by CheckKeywords.
+const MaxKeywordLength = 50
+
+// CheckKeywords is a check for Config.AddCheckProvider that reports
+// empty, duplicate (case-insensitive) and overly long keywords.
+//
+// See CheckProviderFunc
+func CheckKeywords(c *Config) []*ConfigError {
+	var errors []*ConfigError
+	seen := make(map[string]bool, len(c.Keywords))
+
+	for i, keyword := range c.Keywords {
+		var msg string
+		normalized := strings.ToLower(strings.TrimSpace(keyword))
+
+		switch {
+		case normalized == "":
+			msg = fmt.Sprintf("keyword %d is empty", i+1)
+		case seen[normalized]:
+			msg = fmt.Sprintf("keyword '%s' is duplicated", keyword)
+		case utf8.RuneCountInString(keyword) > MaxKeywordLength:
+			msg = fmt.Sprintf("keyword '%s' is longer than %d characters", keyword, MaxKeywordLength)
+		}
+		seen[normalized] = true
+
+		if msg == "" {
+			continue
+		}
+
+		errors = append(errors, &ConfigError{
+			Msg:      msg,
+			Critical: false,
+			Code:     CodeInvalidKeyword,
+			Pointer:  "/keywords/" + strconv.Itoa(i),
+		})
+	}
+
+	return errors
+}
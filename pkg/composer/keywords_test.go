@@ -0,0 +1,24 @@
+package composer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCheckKeywords(t *testing.T) {
+	config := &Config{
+		Keywords: []string{"logging", "", "Logging", "events", strings.Repeat("a", MaxKeywordLength+1), strings.Repeat("я", MaxKeywordLength)},
+	}
+
+	errs := CheckKeywords(config)
+	var pointers []string
+	for _, err := range errs {
+		pointers = append(pointers, err.Pointer)
+	}
+
+	expected := []string{"/keywords/1", "/keywords/2", "/keywords/4"}
+	if !reflect.DeepEqual(pointers, expected) {
+		t.Errorf("expected errors for %v, got %v", expected, pointers)
+	}
+}
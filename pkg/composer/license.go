@@ -0,0 +1,212 @@
+package composer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// CodeInvalidLicense is the code of the errors reported
+// for the license field.
+const CodeInvalidLicense = "invalid-license"
+
+// License is a list of licenses of the package.
+//
+// In composer.json the license is either a string or an array of
+// strings, in the second case the package is available under any of
+// the licenses (disjunctive). Each element may be an SPDX license
+// identifier, an SPDX expression or "proprietary".
+//
+// Examples:
+//
+//	"license": "MIT"
+//	"license": ["LGPL-2.1-only", "GPL-3.0-or-later"]
+//	"license": "(LGPL-2.1-only or GPL-3.0-or-later)"
+//	"license": "proprietary"
+type License []string
+
+// UnmarshalJSON implements the json.Unmarshaler interface
+// for both the string and the array forms.
+func (l *License) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*l = License{single}
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("license must be a string or an array of strings")
+	}
+
+	*l = list
+	return nil
+}
+
+// Expression returns the licenses as a single SPDX expression.
+func (l License) Expression() string {
+	if len(l) == 1 {
+		return l[0]
+	}
+
+	parts := make([]string, 0, len(l))
+	for _, license := range l {
+		if strings.ContainsAny(license, " ") {
+			license = "(" + license + ")"
+		}
+		parts = append(parts, license)
+	}
+	return strings.Join(parts, " OR ")
+}
+
+// IsProprietary reports whether the package is proprietary.
+func (l License) IsProprietary() bool {
+	return len(l) == 1 && l[0] == "proprietary"
+}
+
+// Validate checks each license of the list.
+//
+// See ValidateLicense
+func (l License) Validate() error {
+	for _, license := range l {
+		if err := ValidateLicense(license); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateLicense checks that the license is "proprietary",
+// a known SPDX license identifier or a valid SPDX expression.
+//
+// Expressions may combine licenses with AND, OR and WITH (for license
+// exceptions) in any case, use parentheses, the "+" suffix and the
+// LicenseRef- prefix for custom licenses.
+func ValidateLicense(license string) error {
+	if license == "proprietary" || license == "NONE" || license == "NOASSERTION" {
+		return nil
+	}
+
+	p := &spdxParser{tokens: tokenizeSpdx(license)}
+	if len(p.tokens) == 0 {
+		return fmt.Errorf("license is empty")
+	}
+
+	if err := p.parseExpression(); err != nil {
+		return fmt.Errorf("invalid license '%s': %v", license, err)
+	}
+	if p.pos != len(p.tokens) {
+		return fmt.Errorf("invalid license '%s': unexpected '%s'", license, p.tokens[p.pos])
+	}
+
+	return nil
+}
+
+// IsSpdxLicense reports whether the id is a known SPDX license identifier.
+func IsSpdxLicense(id string) bool {
+	_, ok := spdxLicenses[strings.ToLower(id)]
+	return ok
+}
+
+// spdxParser is a recursive descent parser of SPDX expressions.
+type spdxParser struct {
+	tokens []string
+	pos    int
+}
+
+// parseExpression parses: term {(AND | OR) term}.
+func (p *spdxParser) parseExpression() error {
+	if err := p.parseTerm(); err != nil {
+		return err
+	}
+
+	for p.pos < len(p.tokens) {
+		op := strings.ToUpper(p.tokens[p.pos])
+		if op != "AND" && op != "OR" {
+			return nil
+		}
+		p.pos++
+
+		if err := p.parseTerm(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseTerm parses: "(" expression ")" | license [WITH exception].
+func (p *spdxParser) parseTerm() error {
+	if p.pos == len(p.tokens) {
+		return fmt.Errorf("unexpected end of expression")
+	}
+
+	token := p.tokens[p.pos]
+	p.pos++
+
+	if token == "(" {
+		if err := p.parseExpression(); err != nil {
+			return err
+		}
+		if p.pos == len(p.tokens) || p.tokens[p.pos] != ")" {
+			return fmt.Errorf("missing ')'")
+		}
+		p.pos++
+		return nil
+	}
+
+	if !isSpdxLicenseRef(token) {
+		return fmt.Errorf("unknown license identifier '%s'", token)
+	}
+
+	if p.pos < len(p.tokens) && strings.ToUpper(p.tokens[p.pos]) == "WITH" {
+		p.pos++
+		if p.pos == len(p.tokens) {
+			return fmt.Errorf("missing license exception after WITH")
+		}
+		exception := p.tokens[p.pos]
+		if _, ok := spdxExceptions[strings.ToLower(exception)]; !ok {
+			return fmt.Errorf("unknown license exception '%s'", exception)
+		}
+		p.pos++
+	}
+
+	return nil
+}
+
+// isSpdxLicenseRef reports whether the token is a known license
+// identifier, optionally with the "+" suffix, or a custom license reference.
+func isSpdxLicenseRef(token string) bool {
+	if strings.HasPrefix(token, "LicenseRef-") || strings.HasPrefix(token, "DocumentRef-") {
+		return len(token) > len("LicenseRef-")
+	}
+	return IsSpdxLicense(strings.TrimSuffix(token, "+"))
+}
+
+// tokenizeSpdx splits the expression into identifiers, operators and parentheses.
+func tokenizeSpdx(expr string) []string {
+	var tokens []string
+	var current strings.Builder
+
+	flush := func() {
+		if current.Len() != 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+
+	for _, r := range expr {
+		switch r {
+		case '(', ')':
+			flush()
+			tokens = append(tokens, string(r))
+		case ' ', '\t', '\n':
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
+
+	return tokens
+}
@@ -0,0 +1,49 @@
+package composer
+
+import (
+	"testing"
+)
+
+func TestLicense(t *testing.T) {
+	tests := []struct {
+		License string
+		Valid   bool
+	}{
+		{License: "MIT", Valid: true},
+		{License: "mit", Valid: true},
+		{License: "proprietary", Valid: true},
+		{License: "GPL-2.0+", Valid: true},
+		{License: "MIT OR GPL-2.0-only", Valid: true},
+		{License: "(LGPL-2.1-only or GPL-3.0-or-later)", Valid: true},
+		{License: "Apache-2.0 AND (MIT OR BSD-3-Clause)", Valid: true},
+		{License: "GPL-2.0-only WITH Classpath-exception-2.0", Valid: true},
+		{License: "LicenseRef-My-License", Valid: true},
+		{License: "", Valid: false},
+		{License: "MY-LICENSE", Valid: false},
+		{License: "MIT OR", Valid: false},
+		{License: "(MIT", Valid: false},
+		{License: "MIT GPL-2.0-only", Valid: false},
+		{License: "GPL-2.0-only WITH MIT", Valid: false},
+		{License: "MIT OR proprietary", Valid: false},
+	}
+
+	for _, test := range tests {
+		err := ValidateLicense(test.License)
+		if (err == nil) != test.Valid {
+			t.Errorf("%s: expected valid: %v, got error: %v", test.License, test.Valid, err)
+		}
+	}
+
+	config, errs := NewConfigFromData([]byte(`{"version": "1.0.0", "license": ["MIT", "unknown"]}`), "composer.json")
+	if errs.Len() != 1 || errs.Errors[0].Pointer != "/license/1" {
+		t.Errorf("expected a single error for the second license, got %v", errs)
+	}
+	if config.License.Expression() != "MIT OR unknown" {
+		t.Errorf("unexpected expression: %s", config.License.Expression())
+	}
+
+	config, errs = NewConfigFromData([]byte(`{"version": "1.0.0", "license": "proprietary"}`), "composer.json")
+	if errs != nil || !config.License.IsProprietary() {
+		t.Errorf("expected a proprietary license, got %v", errs)
+	}
+}
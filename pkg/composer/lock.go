@@ -0,0 +1,180 @@
+package composer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// Lock is the composer.lock file, which records the exact versions
+// of the packages installed for the config.
+//
+// Only the fields needed to inspect the locked packages are read.
+type Lock struct {
+	ContentHash string `json:"content-hash"`
+	// Packages are the packages installed with --no-dev.
+	Packages []LockPackage `json:"packages"`
+	// PackagesDev are the packages installed only in development.
+	PackagesDev      []LockPackage `json:"packages-dev"`
+	MinimumStability string        `json:"minimum-stability"`
+	PreferStable     bool          `json:"prefer-stable"`
+	PreferLowest     bool          `json:"prefer-lowest"`
+	// Platform and PlatformDev are the platform requirements
+	// of the root package, such as php and ext-json.
+	Platform    LockPlatform `json:"platform"`
+	PlatformDev LockPlatform `json:"platform-dev"`
+	// PluginApiVersion is the version of the plugin API of the composer
+	// that wrote the lock. It is empty in locks written by composer
+	// before 1.10.
+	PluginApiVersion string `json:"plugin-api-version"`
+}
+
+// LockPackage is a package locked in composer.lock.
+type LockPackage struct {
+	Name        string            `json:"name"`
+	Version     string            `json:"version"`
+	Type        string            `json:"type"`
+	Description string            `json:"description"`
+	Homepage    string            `json:"homepage"`
+	License     License           `json:"license"`
+	Time        string            `json:"time"`
+	Source      *PackageSource    `json:"source"`
+	Dist        *PackageSource    `json:"dist"`
+	Require     map[string]string `json:"require"`
+	RequireDev  map[string]string `json:"require-dev"`
+	Conflict    map[string]string `json:"conflict"`
+	Replace     map[string]string `json:"replace"`
+	Provide     map[string]string `json:"provide"`
+	Suggest     map[string]string `json:"suggest"`
+	Autoload    Autoload          `json:"autoload"`
+	Bin         Binaries          `json:"bin"`
+	TargetDir   string            `json:"target-dir"`
+}
+
+// PackageSource is the source or the dist of a locked package.
+type PackageSource struct {
+	// Type is the type of the source (git, hg, svn, path)
+	// or of the dist archive (zip, tar, path).
+	Type      string `json:"type"`
+	Url       string `json:"url"`
+	Reference string `json:"reference"`
+	// Shasum is the sha1 of the dist archive, it is often empty,
+	// for example, for the dists of GitHub.
+	Shasum string `json:"shasum"`
+}
+
+// LockPlatform is a map of the platform packages to the constraints.
+//
+// PHP encodes an empty map as an empty array,
+// so both forms are accepted.
+type LockPlatform map[string]string
+
+// UnmarshalJSON implements the json.Unmarshaler interface
+// for both the object and the empty array forms.
+func (p *LockPlatform) UnmarshalJSON(data []byte) error {
+	var list []json.RawMessage
+	if err := json.Unmarshal(data, &list); err == nil {
+		if len(list) != 0 {
+			return fmt.Errorf("platform must be an object")
+		}
+		*p = LockPlatform{}
+		return nil
+	}
+
+	var m map[string]string
+	if err := json.Unmarshal(data, &m); err != nil {
+		return fmt.Errorf("platform must be an object")
+	}
+
+	*p = m
+	return nil
+}
+
+// LoadLock reads the lock from the file.
+//
+// See Config.LockPath
+func LoadLock(filename string) (*Lock, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	lock, err := ParseLock(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	return lock, nil
+}
+
+// ParseLock parses the contents of composer.lock.
+//
+// Locks written by composer 1 are also accepted, see Lock.IsLegacy.
+func ParseLock(data []byte) (*Lock, error) {
+	var lock Lock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, err
+	}
+
+	// Early versions of composer 1 write the hash instead of the content-hash.
+	if lock.ContentHash == "" {
+		var legacy struct {
+			Hash string `json:"hash"`
+		}
+		_ = json.Unmarshal(data, &legacy)
+		lock.ContentHash = legacy.Hash
+	}
+
+	for _, pkg := range lock.AllPackages() {
+		if pkg.Name == "" {
+			return nil, fmt.Errorf("locked package without a name")
+		}
+	}
+
+	return &lock, nil
+}
+
+// LockPath returns the path to the composer.lock of the config,
+// as in composer, it is the path to the config with the .lock
+// extension instead of .json.
+func (c *Config) LockPath() string {
+	return strings.TrimSuffix(c.Path, filepath.Ext(c.Path)) + ".lock"
+}
+
+// IsLegacy returns true if the lock was written by composer 1.
+//
+// Composer before 1.10 does not record the version of the plugin
+// API, and composer 1.10 records a 1.x version, such as 1.1.0.
+func (l *Lock) IsLegacy() bool {
+	if l.PluginApiVersion == "" {
+		return true
+	}
+	api, err := parsePlatformVersion(l.PluginApiVersion)
+	return err == nil && api.Major < 2
+}
+
+// AllPackages returns the packages and the dev packages.
+func (l *Lock) AllPackages() []LockPackage {
+	res := make([]LockPackage, 0, len(l.Packages)+len(l.PackagesDev))
+	res = append(res, l.Packages...)
+	return append(res, l.PackagesDev...)
+}
+
+// Package returns the locked package with the passed name,
+// dev is true if the package is installed only in development.
+//
+// As in composer, package names are case-insensitive.
+func (l *Lock) Package(name string) (pkg *LockPackage, dev bool, ok bool) {
+	for i := range l.Packages {
+		if strings.EqualFold(l.Packages[i].Name, name) {
+			return &l.Packages[i], false, true
+		}
+	}
+	for i := range l.PackagesDev {
+		if strings.EqualFold(l.PackagesDev[i].Name, name) {
+			return &l.PackagesDev[i], true, true
+		}
+	}
+	return nil, false, false
+}
@@ -0,0 +1,41 @@
+package composer
+
+import (
+	"testing"
+)
+
+func TestParseLock(t *testing.T) {
+	lock, err := ParseLock([]byte(`{
+		"content-hash": "abc",
+		"packages": [
+			{"name": "monolog/monolog", "version": "2.9.1", "license": ["MIT"],
+			 "source": {"type": "git", "url": "https://github.com/Seldaek/monolog.git", "reference": "f259e2b"},
+			 "require": {"php": ">=7.2", "psr/log": "^1.0.1 || ^2.0 || ^3.0"}}
+		],
+		"packages-dev": [
+			{"name": "phpunit/phpunit", "version": "9.6.0"}
+		],
+		"platform": [],
+		"platform-dev": {"ext-xdebug": "*"},
+		"plugin-api-version": "2.6.0"
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pkg, dev, ok := lock.Package("Monolog/Monolog")
+	if !ok || dev || pkg.Version != "2.9.1" || pkg.Source.Reference != "f259e2b" {
+		t.Errorf("unexpected monolog/monolog: %+v", pkg)
+	}
+	if _, dev, ok := lock.Package("phpunit/phpunit"); !ok || !dev {
+		t.Errorf("expected phpunit/phpunit to be a dev package")
+	}
+	if len(lock.Platform) != 0 || lock.PlatformDev["ext-xdebug"] != "*" {
+		t.Errorf("unexpected platform: %v, %v", lock.Platform, lock.PlatformDev)
+	}
+
+	config := &Config{Path: "/project/composer.json"}
+	if path := config.LockPath(); path != "/project/composer.lock" {
+		t.Errorf("unexpected lock path: %s", path)
+	}
+}
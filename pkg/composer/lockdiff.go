@@ -0,0 +1,212 @@
+package composer
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Kinds of the changes between two locks.
+const (
+	LockChangeAdded         = "added"
+	LockChangeRemoved       = "removed"
+	LockChangeUpgraded      = "upgraded"
+	LockChangeDowngraded    = "downgraded"
+	LockChangeReferenceOnly = "reference-only"
+)
+
+// Severities of upgrades and downgrades.
+const (
+	SeverityMajor = "major"
+	SeverityMinor = "minor"
+	SeverityPatch = "patch"
+)
+
+// LockChange is a change of a single package between two locks.
+type LockChange struct {
+	Name string `json:"name"`
+	// Kind is one of the LockChange* constants.
+	Kind string `json:"kind"`
+	// Dev is true if the package is installed only in development,
+	// for removed packages, in the old lock.
+	Dev bool `json:"dev"`
+
+	OldVersion   string `json:"old_version,omitempty"`
+	NewVersion   string `json:"new_version,omitempty"`
+	OldReference string `json:"old_reference,omitempty"`
+	NewReference string `json:"new_reference,omitempty"`
+
+	// Severity is the semver severity of an upgrade or a downgrade,
+	// one of the Severity* constants. It is empty if one of the
+	// versions is a branch, as dev-master, such a change is reported
+	// as an upgrade.
+	Severity string `json:"severity,omitempty"`
+}
+
+// LockChanges is the list of changes between two locks, see LockDiff.
+type LockChanges []LockChange
+
+// LockDiff returns the changes of the packages between two locks
+// sorted by package name.
+//
+// A package is upgraded or downgraded if its version changed, and
+// changed only by reference if the version is the same but the commit
+// is different, which is usual for branches like dev-master.
+func LockDiff(oldLock, newLock *Lock) LockChanges {
+	type locked struct {
+		pkg LockPackage
+		dev bool
+	}
+
+	collect := func(lock *Lock) map[string]locked {
+		res := map[string]locked{}
+		for _, pkg := range lock.Packages {
+			res[strings.ToLower(pkg.Name)] = locked{pkg: pkg}
+		}
+		for _, pkg := range lock.PackagesDev {
+			res[strings.ToLower(pkg.Name)] = locked{pkg: pkg, dev: true}
+		}
+		return res
+	}
+
+	oldPackages := collect(oldLock)
+	newPackages := collect(newLock)
+
+	var changes LockChanges
+	for key, old := range oldPackages {
+		if _, ok := newPackages[key]; !ok {
+			changes = append(changes, LockChange{
+				Name:         old.pkg.Name,
+				Kind:         LockChangeRemoved,
+				Dev:          old.dev,
+				OldVersion:   old.pkg.Version,
+				OldReference: old.pkg.reference(),
+			})
+		}
+	}
+
+	for key, cur := range newPackages {
+		change := LockChange{
+			Name:         cur.pkg.Name,
+			Dev:          cur.dev,
+			NewVersion:   cur.pkg.Version,
+			NewReference: cur.pkg.reference(),
+		}
+
+		old, ok := oldPackages[key]
+		if !ok {
+			change.Kind = LockChangeAdded
+			changes = append(changes, change)
+			continue
+		}
+
+		change.OldVersion = old.pkg.Version
+		change.OldReference = old.pkg.reference()
+
+		switch {
+		case change.OldVersion == change.NewVersion:
+			if change.OldReference == change.NewReference {
+				continue
+			}
+			change.Kind = LockChangeReferenceOnly
+		default:
+			change.Kind, change.Severity = classifyVersionChange(change.OldVersion, change.NewVersion)
+		}
+
+		changes = append(changes, change)
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return strings.ToLower(changes[i].Name) < strings.ToLower(changes[j].Name)
+	})
+
+	return changes
+}
+
+// classifyVersionChange returns the kind and the severity
+// of the change between the versions.
+func classifyVersionChange(oldVersion, newVersion string) (kind string, severity string) {
+	oldParsed, err := parsePlatformVersion(oldVersion)
+	if err != nil {
+		return LockChangeUpgraded, ""
+	}
+	newParsed, err := parsePlatformVersion(newVersion)
+	if err != nil {
+		return LockChangeUpgraded, ""
+	}
+
+	kind = LockChangeUpgraded
+	if newParsed.Compare(oldParsed) < 0 {
+		kind = LockChangeDowngraded
+	}
+
+	switch {
+	case oldParsed.Major != newParsed.Major:
+		severity = SeverityMajor
+	case oldParsed.Minor != newParsed.Minor:
+		severity = SeverityMinor
+	default:
+		severity = SeverityPatch
+	}
+
+	return kind, severity
+}
+
+// reference returns the commit of the package,
+// preferring the source over the dist.
+func (p *LockPackage) reference() string {
+	if p.Source != nil && p.Source.Reference != "" {
+		return p.Source.Reference
+	}
+	if p.Dist != nil {
+		return p.Dist.Reference
+	}
+	return ""
+}
+
+// JSON returns the changes as a JSON array.
+func (c LockChanges) JSON() ([]byte, error) {
+	if c == nil {
+		c = LockChanges{}
+	}
+	return json.MarshalIndent(c, "", "  ")
+}
+
+// Markdown returns the changes as a Markdown table
+// suitable for pull request comments.
+func (c LockChanges) Markdown() string {
+	if len(c) == 0 {
+		return "No changes in composer.lock.\n"
+	}
+
+	var b strings.Builder
+	b.WriteString("| Package | Change | From | To | Severity |\n")
+	b.WriteString("|---|---|---|---|---|\n")
+
+	for _, change := range c {
+		name := markdownCode(change.Name)
+		if change.Dev {
+			name += " (dev)"
+		}
+
+		from, to := change.OldVersion, change.NewVersion
+		if change.Kind == LockChangeReferenceOnly {
+			from += " " + shortReference(change.OldReference)
+			to += " " + shortReference(change.NewReference)
+		}
+
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n",
+			name, change.Kind, markdownCode(from), markdownCode(to), change.Severity)
+	}
+
+	return b.String()
+}
+
+// shortReference returns the abbreviated commit hash.
+func shortReference(ref string) string {
+	if len(ref) > 7 {
+		return ref[:7]
+	}
+	return ref
+}
@@ -0,0 +1,61 @@
+package composer
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLockDiff(t *testing.T) {
+	oldLock := &Lock{
+		Packages: []LockPackage{
+			{Name: "foo/major", Version: "1.9.0"},
+			{Name: "foo/minor", Version: "v1.1.0"},
+			{Name: "foo/down", Version: "2.0.1"},
+			{Name: "foo/branch", Version: "dev-master", Source: &PackageSource{Reference: "1111111111"}},
+			{Name: "foo/same", Version: "1.0.0"},
+			{Name: "foo/removed", Version: "1.0.0"},
+		},
+	}
+	newLock := &Lock{
+		Packages: []LockPackage{
+			{Name: "foo/major", Version: "2.0.0"},
+			{Name: "foo/minor", Version: "v1.2.0"},
+			{Name: "foo/down", Version: "2.0.0"},
+			{Name: "foo/branch", Version: "dev-master", Source: &PackageSource{Reference: "2222222222"}},
+			{Name: "foo/same", Version: "1.0.0"},
+		},
+		PackagesDev: []LockPackage{
+			{Name: "foo/added", Version: "0.1.0"},
+		},
+	}
+
+	changes := LockDiff(oldLock, newLock)
+
+	expected := LockChanges{
+		{Name: "foo/added", Kind: LockChangeAdded, Dev: true, NewVersion: "0.1.0"},
+		{Name: "foo/branch", Kind: LockChangeReferenceOnly, OldVersion: "dev-master", NewVersion: "dev-master", OldReference: "1111111111", NewReference: "2222222222"},
+		{Name: "foo/down", Kind: LockChangeDowngraded, OldVersion: "2.0.1", NewVersion: "2.0.0", Severity: SeverityPatch},
+		{Name: "foo/major", Kind: LockChangeUpgraded, OldVersion: "1.9.0", NewVersion: "2.0.0", Severity: SeverityMajor},
+		{Name: "foo/minor", Kind: LockChangeUpgraded, OldVersion: "v1.1.0", NewVersion: "v1.2.0", Severity: SeverityMinor},
+		{Name: "foo/removed", Kind: LockChangeRemoved, OldVersion: "1.0.0"},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("mismatch changes:\nwant: %+v\nhave: %+v", expected, changes)
+	}
+
+	markdown := changes.Markdown()
+	if !strings.Contains(markdown, "| <code>foo/branch</code> | reference-only | <code>dev-master 1111111</code> | <code>dev-master 2222222</code> |  |\n") {
+		t.Errorf("unexpected markdown:\n%s", markdown)
+	}
+
+	data, err := changes.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded LockChanges
+	if err := json.Unmarshal(data, &decoded); err != nil || !reflect.DeepEqual(decoded, expected) {
+		t.Errorf("unexpected JSON: %s", data)
+	}
+}
@@ -0,0 +1,148 @@
+package composer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CodeAmbiguousNamespace is the code of the errors reported
+// by ResolveNamespace.
+const CodeAmbiguousNamespace = "ambiguous-namespace"
+
+// NamespaceStrategy describes how ResolveNamespace chooses
+// between several configs that can satisfy a namespace.
+type NamespaceStrategy int
+
+const (
+	// NamespaceLongestPrefix chooses the config with the longest
+	// matching psr-4 prefix, the first config wins a tie.
+	NamespaceLongestPrefix NamespaceStrategy = iota
+	// NamespaceRootWins chooses the first (root) config if it can
+	// satisfy the namespace, otherwise the longest prefix wins.
+	NamespaceRootWins
+	// NamespaceError does not choose, a critical error
+	// is reported if several configs can satisfy the namespace.
+	NamespaceError
+)
+
+// NamespaceMatch is a psr-4 mapping that satisfies a namespace.
+type NamespaceMatch struct {
+	// Config is the config the mapping belongs to.
+	Config *Config
+	// Prefix is the psr-4 namespace prefix, for example, App\.
+	Prefix string
+	// Paths are the paths the prefix is mapped to.
+	Paths []string
+	// Dev is true if the mapping is in autoload-dev.
+	Dev bool
+}
+
+// ResolveNamespace looks for the psr-4 mapping for the namespace
+// in several configs, for example, in the packages of a workspace.
+//
+// Unlike Config.Psr4PathForNamespace, ambiguities are not resolved
+// silently: if several configs can satisfy the namespace, an error
+// is reported for each candidate that was not chosen, and the choice
+// is made by the strategy. The errors refer to the first config.
+// With the NamespaceError strategy, a single critical error
+// lists all candidates.
+//
+// If no config can satisfy the namespace, nil is returned.
+func ResolveNamespace(name string, strategy NamespaceStrategy, configs ...*Config) (*NamespaceMatch, *ConfigErrors) {
+	var candidates []NamespaceMatch
+	for _, config := range configs {
+		if match, ok := config.namespaceMatch(name); ok {
+			candidates = append(candidates, match)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+	if len(candidates) == 1 {
+		return &candidates[0], nil
+	}
+
+	errors := &ConfigErrors{Config: configs[0]}
+
+	var chosen *NamespaceMatch
+	switch strategy {
+	case NamespaceRootWins:
+		if candidates[0].Config == configs[0] {
+			chosen = &candidates[0]
+			break
+		}
+		chosen = longestNamespaceMatch(candidates)
+	case NamespaceLongestPrefix:
+		chosen = longestNamespaceMatch(candidates)
+	}
+
+	if chosen == nil {
+		mappings := make([]string, 0, len(candidates))
+		for _, candidate := range candidates {
+			mappings = append(mappings, fmt.Sprintf("%s to '%s' in %s", candidate.Prefix, strings.Join(candidate.Paths, "', '"), candidate.Config.Path))
+		}
+
+		errors.Add(&ConfigError{
+			Msg:      fmt.Sprintf("namespace %s is ambiguous, it is mapped by %s", name, strings.Join(mappings, ", ")),
+			Critical: true,
+			Code:     CodeAmbiguousNamespace,
+		})
+	}
+
+	for i := range candidates {
+		candidate := &candidates[i]
+		if chosen == nil || candidate == chosen {
+			continue
+		}
+
+		errors.Add(&ConfigError{
+			Msg: fmt.Sprintf("namespace %s is resolved to %s in %s, but is also mapped by %s to '%s' in %s",
+				name, chosen.Prefix, chosen.Config.Path, candidate.Prefix, strings.Join(candidate.Paths, "', '"), candidate.Config.Path),
+			Critical: false,
+			Code:     CodeAmbiguousNamespace,
+		})
+	}
+
+	if errors.Len() == 0 && len(errors.Suppressed) == 0 {
+		errors = nil
+	}
+	return chosen, errors
+}
+
+// longestNamespaceMatch returns the candidate with the longest prefix,
+// the first one wins a tie.
+func longestNamespaceMatch(candidates []NamespaceMatch) *NamespaceMatch {
+	longest := &candidates[0]
+	for i := range candidates[1:] {
+		if len(candidates[i+1].Prefix) > len(longest.Prefix) {
+			longest = &candidates[i+1]
+		}
+	}
+	return longest
+}
+
+// namespaceMatch returns the mapping with the longest prefix for the
+// namespace in autoload, or in autoload-dev if autoload has none.
+func (c *Config) namespaceMatch(name string) (NamespaceMatch, bool) {
+	if prefix, ok := c.Autoload.psr4Prefix(name); ok {
+		return NamespaceMatch{Config: c, Prefix: prefix, Paths: c.Autoload.Psr4[prefix]}, true
+	}
+	if prefix, ok := c.AutoloadDev.psr4Prefix(name); ok {
+		return NamespaceMatch{Config: c, Prefix: prefix, Paths: c.AutoloadDev.Psr4[prefix], Dev: true}, true
+	}
+	return NamespaceMatch{}, false
+}
+
+// psr4Prefix returns the longest psr-4 prefix of the namespace,
+// the empty fallback prefix matches any namespace.
+func (a *Autoload) psr4Prefix(name string) (found string, ok bool) {
+	name = name + `\`
+
+	for prefix := range a.Psr4 {
+		if strings.HasPrefix(name, prefix) && (!ok || len(prefix) > len(found)) {
+			found, ok = prefix, true
+		}
+	}
+	return found, ok
+}
@@ -0,0 +1,47 @@
+package composer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResolveNamespace(t *testing.T) {
+	root, _ := NewConfigFromData([]byte(`{"version": "1.0.0", "autoload": {"psr-4": {"App\\": "src/"}}}`), "root/composer.json")
+	pkg, _ := NewConfigFromData([]byte(`{"version": "1.0.0", "autoload": {"psr-4": {"App\\Billing\\": "lib/"}}}`), "pkg/composer.json")
+	other, _ := NewConfigFromData([]byte(`{"version": "1.0.0", "autoload-dev": {"psr-4": {"Other\\": "tests/"}}}`), "other/composer.json")
+
+	tests := []struct {
+		strategy NamespaceStrategy
+		config   *Config
+		critical bool
+	}{
+		{strategy: NamespaceLongestPrefix, config: pkg},
+		{strategy: NamespaceRootWins, config: root},
+		{strategy: NamespaceError, config: nil, critical: true},
+	}
+
+	for _, tt := range tests {
+		match, errs := ResolveNamespace(`App\Billing\Invoice`, tt.strategy, root, pkg, other)
+		if tt.config == nil && match != nil || tt.config != nil && (match == nil || match.Config != tt.config) {
+			t.Errorf("strategy %d: unexpected match %+v", tt.strategy, match)
+		}
+		if errs.Len() != 1 || errs.Errors[0].Code != CodeAmbiguousNamespace || errs.Errors[0].Critical != tt.critical {
+			t.Errorf("strategy %d: unexpected errors %v", tt.strategy, errs)
+		}
+	}
+
+	match, errs := ResolveNamespace(`Other\Unit`, NamespaceError, root, pkg, other)
+	if errs != nil || match == nil || match.Config != other || !match.Dev || !reflect.DeepEqual(match.Paths, []string{"tests/"}) {
+		t.Errorf("unexpected unambiguous match %+v, %v", match, errs)
+	}
+
+	if match, _ := ResolveNamespace(`Unknown`, NamespaceError, root, pkg, other); match != nil {
+		t.Errorf("expected no match, got %+v", match)
+	}
+
+	fallback, _ := NewConfigFromData([]byte(`{"version": "1.0.0", "autoload": {"psr-4": {"": "legacy/"}}}`), "fallback/composer.json")
+	match, errs = ResolveNamespace(`Unknown`, NamespaceError, root, fallback)
+	if errs != nil || match == nil || match.Config != fallback || match.Prefix != "" {
+		t.Errorf("expected the fallback prefix to match, got %+v, %v", match, errs)
+	}
+}
@@ -0,0 +1,148 @@
+package composer
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// licenseFilePrefixes are the prefixes of the names of the license
+// files in the package dirs, such as LICENSE, LICENSE.md, LICENCE-MIT
+// and COPYING.txt.
+var licenseFilePrefixes = []string{"LICENSE", "LICENCE", "COPYING", "NOTICE"}
+
+// LicenseNotice is the license of an installed package,
+// see Installed.LicenseNotices.
+type LicenseNotice struct {
+	Name    string
+	Version string
+	// License is the license of the package from installed.json,
+	// usually SPDX license identifiers.
+	License License
+	// Files are the sorted paths of the license files
+	// relative to the package dir.
+	Files []string
+	// Text is the contents of the license files.
+	Text string
+}
+
+// LicenseNotices is the list of licenses of the installed packages.
+type LicenseNotices []LicenseNotice
+
+// LicenseNotices locates the license files in the dirs of the installed
+// packages, the dev packages are included if dev is true, and returns
+// the licenses of the packages sorted by name.
+//
+// The license files are the files in the root of the package dir whose
+// names start with LICENSE, LICENCE, COPYING or NOTICE, in any case.
+// Packages without a dir, such as metapackages, and packages without
+// license files are included with an empty text.
+func (i *Installed) LicenseNotices(vendorDir string, dev bool) (LicenseNotices, error) {
+	var notices LicenseNotices
+
+	for _, pkg := range i.Packages {
+		if _, isDev, _ := i.Package(pkg.Name); isDev && !dev {
+			continue
+		}
+
+		// Composer 1 does not record the install path.
+		dir := filepath.Join(vendorDir, filepath.FromSlash(pkg.Name))
+		if pkg.InstallPath != "" {
+			dir = filepath.Join(vendorDir, "composer", filepath.FromSlash(pkg.InstallPath))
+		}
+
+		notice := LicenseNotice{
+			Name:    pkg.Name,
+			Version: pkg.Version,
+			License: pkg.License,
+		}
+
+		files, err := licenseFiles(dir)
+		if err != nil {
+			return nil, err
+		}
+
+		var texts []string
+		for _, file := range files {
+			data, err := ioutil.ReadFile(filepath.Join(dir, file))
+			if err != nil {
+				return nil, err
+			}
+			notice.Files = append(notice.Files, file)
+			texts = append(texts, strings.TrimSpace(string(data)))
+		}
+		notice.Text = strings.Join(texts, "\n\n")
+
+		notices = append(notices, notice)
+	}
+
+	sort.Slice(notices, func(i, j int) bool {
+		return notices[i].Name < notices[j].Name
+	})
+
+	return notices, nil
+}
+
+// licenseFiles returns the sorted names of the license files in the dir.
+func licenseFiles(dir string) ([]string, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		name := strings.ToUpper(entry.Name())
+		for _, prefix := range licenseFilePrefixes {
+			if strings.HasPrefix(name, prefix) {
+				files = append(files, entry.Name())
+				break
+			}
+		}
+	}
+
+	return files, nil
+}
+
+// Text returns the notices as a third-party licenses document
+// to be shipped with a release: for each package its name, version,
+// license and the full text of its license files.
+func (n LicenseNotices) Text() string {
+	var b strings.Builder
+	b.WriteString("THIRD-PARTY SOFTWARE NOTICES\n\n")
+	b.WriteString("This software includes the following third-party packages.\n")
+
+	for _, notice := range n {
+		b.WriteString("\n")
+		b.WriteString(strings.Repeat("=", 72))
+		b.WriteString("\n")
+		fmt.Fprintf(&b, "%s %s\n", notice.Name, notice.Version)
+
+		license := notice.License.Expression()
+		if len(notice.License) == 0 {
+			license = "NOASSERTION"
+		}
+		fmt.Fprintf(&b, "License: %s\n", license)
+		b.WriteString(strings.Repeat("=", 72))
+		b.WriteString("\n\n")
+
+		if notice.Text == "" {
+			b.WriteString("No license file found.\n")
+			continue
+		}
+		b.WriteString(notice.Text)
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
@@ -0,0 +1,92 @@
+package composer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLicenseNotices(t *testing.T) {
+	dir, err := ioutil.TempDir("", "composer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"psr/log/LICENSE":                  "Copyright (c) 2012 PHP Framework Interoperability Group\n",
+		"psr/log/src/LICENSE":              "not the license of the package",
+		"symfony/polyfill-php80/LICENSE":   "Copyright (c) 2020-present Fabien Potencier",
+		"symfony/polyfill-php80/NOTICE.md": "Portions of this software are based on PHP.",
+		"phpunit/phpunit/LICENSE":          "Copyright (c) 2001-2023, Sebastian Bergmann",
+		"legacy/lib/copying.txt":           "GNU GENERAL PUBLIC LICENSE",
+	}
+	for file, content := range files {
+		path := filepath.Join(dir, "vendor", filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	installed, err := ParseInstalled([]byte(`{
+		"packages": [
+			{"name": "symfony/polyfill-php80", "version": "v1.28.0", "license": ["MIT"], "install-path": "../symfony/polyfill-php80"},
+			{"name": "psr/log", "version": "3.0.0", "license": ["MIT"], "install-path": "../psr/log"},
+			{"name": "legacy/lib", "version": "1.0.0", "license": ["GPL-2.0-only", "MIT"], "install-path": "../legacy/lib"},
+			{"name": "my/metapackage", "version": "1.0.0", "type": "metapackage", "install-path": null},
+			{"name": "phpunit/phpunit", "version": "10.5.1", "license": ["BSD-3-Clause"], "install-path": "../phpunit/phpunit"}
+		],
+		"dev-package-names": ["phpunit/phpunit"]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	notices, err := installed.LicenseNotices(filepath.Join(dir, "vendor"), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, notice := range notices {
+		names = append(names, notice.Name+" "+strings.Join(notice.Files, ","))
+	}
+	expected := []string{
+		"legacy/lib copying.txt",
+		"my/metapackage ",
+		"psr/log LICENSE",
+		"symfony/polyfill-php80 LICENSE,NOTICE.md",
+	}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("unexpected notices: %v", names)
+	}
+
+	text := notices.Text()
+	for _, part := range []string{
+		"psr/log 3.0.0\nLicense: MIT\n",
+		"legacy/lib 1.0.0\nLicense: GPL-2.0-only OR MIT\n",
+		"Copyright (c) 2020-present Fabien Potencier\n\nPortions of this software are based on PHP.\n",
+		"my/metapackage 1.0.0\nLicense: NOASSERTION\n" + strings.Repeat("=", 72) + "\n\nNo license file found.\n",
+	} {
+		if !strings.Contains(text, part) {
+			t.Errorf("expected %q in the notices:\n%s", part, text)
+		}
+	}
+	if strings.Contains(text, "not the license") || strings.Contains(text, "Bergmann") {
+		t.Errorf("unexpected license texts in the notices:\n%s", text)
+	}
+
+	notices, err = installed.LicenseNotices(filepath.Join(dir, "vendor"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notices) != 5 || notices[2].Name != "phpunit/phpunit" {
+		t.Errorf("expected the dev packages, got %+v", notices)
+	}
+}
@@ -0,0 +1,245 @@
+package composer
+
+import (
+	"sort"
+	"strings"
+)
+
+// Origins of the locked packages, see Config.PackageOrigins.
+const (
+	// OriginDirect is a package required in the require section.
+	OriginDirect = "direct"
+	// OriginDirectDev is a package required only in the
+	// require-dev section and not needed in production.
+	OriginDirectDev = "direct-dev"
+	// OriginTransitive is a dependency of a production package.
+	OriginTransitive = "transitive"
+	// OriginTransitiveDev is a dependency of dev packages only.
+	OriginTransitiveDev = "transitive-dev"
+	// OriginUnused is a locked package that nothing requires,
+	// usually the lock is out of date.
+	OriginUnused = "unused"
+)
+
+// PackageOrigins maps the names of the locked packages to their origins.
+type PackageOrigins map[string]string
+
+// PackageOrigins classifies each package of the lock of the config
+// by why it is installed, see the Origin* constants.
+//
+// A package needed in production is never dev-only, even if it is
+// also listed in require-dev. Requirements are resolved through the
+// replace and provide sections of the locked packages, platform
+// packages like php and ext-json are skipped.
+func (c *Config) PackageOrigins(lock *Lock) PackageOrigins {
+	graph := newLockGraph(lock)
+	prod := graph.reachable(c.Require)
+	dev := graph.reachable(c.RequireDev)
+
+	direct := graph.resolveAll(c.Require)
+	directDev := graph.resolveAll(c.RequireDev)
+
+	origins := make(PackageOrigins, len(graph.packages))
+	for key, pkg := range graph.packages {
+		var origin string
+		switch {
+		case direct[key]:
+			origin = OriginDirect
+		case prod[key]:
+			origin = OriginTransitive
+		case directDev[key]:
+			origin = OriginDirectDev
+		case dev[key]:
+			origin = OriginTransitiveDev
+		default:
+			origin = OriginUnused
+		}
+		origins[pkg.Name] = origin
+	}
+
+	return origins
+}
+
+// DevOnlyPackages returns the sorted names of the packages
+// that are installed only in development.
+func (o PackageOrigins) DevOnlyPackages() []string {
+	var names []string
+	for name, origin := range o {
+		if origin == OriginDirectDev || origin == OriginTransitiveDev {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LockGraph is the graph of the requirements between the locked
+// packages, see NewLockGraph.
+//
+// Requirements are resolved through the replace and provide sections
+// of the locked packages, platform packages are skipped.
+type LockGraph struct {
+	graph *lockGraph
+}
+
+// NewLockGraph returns the graph of the packages of the lock.
+func NewLockGraph(lock *Lock) *LockGraph {
+	return &LockGraph{graph: newLockGraph(lock)}
+}
+
+// Package returns the locked package with the passed name.
+func (g *LockGraph) Package(name string) (*LockPackage, bool) {
+	pkg, ok := g.graph.packages[strings.ToLower(name)]
+	return pkg, ok
+}
+
+// Dependencies returns the sorted names of the locked packages
+// satisfying the requirements of the package.
+func (g *LockGraph) Dependencies(name string) []string {
+	pkg, ok := g.Package(name)
+	if !ok {
+		return nil
+	}
+
+	var require map[string]string
+	for dep, constraint := range pkg.Require {
+		if IsPlatformPackage(dep) {
+			continue
+		}
+		if require == nil {
+			require = map[string]string{}
+		}
+		require[dep] = constraint
+	}
+	return g.names(g.graph.resolveAll(require))
+}
+
+// Reachable returns the sorted names of the locked packages needed
+// by the requirements, that is, the packages satisfying them and
+// all their dependencies.
+func (g *LockGraph) Reachable(require map[string]string) []string {
+	return g.names(g.graph.reachable(require))
+}
+
+// names returns the sorted names of the packages with the keys.
+func (g *LockGraph) names(keys map[string]bool) []string {
+	names := make([]string, 0, len(keys))
+	for key := range keys {
+		names = append(names, g.graph.packages[key].Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lockGraph is the graph of the requirements between locked packages.
+type lockGraph struct {
+	// packages are the locked packages by lowercased name.
+	packages map[string]*LockPackage
+	// providers maps the lowercased names of the replaced and
+	// provided packages to the packages replacing or providing them.
+	providers map[string][]string
+}
+
+func newLockGraph(lock *Lock) *lockGraph {
+	g := &lockGraph{
+		packages:  map[string]*LockPackage{},
+		providers: map[string][]string{},
+	}
+
+	for _, packages := range [][]LockPackage{lock.Packages, lock.PackagesDev} {
+		for i := range packages {
+			pkg := &packages[i]
+			key := strings.ToLower(pkg.Name)
+			g.packages[key] = pkg
+
+			for _, links := range []map[string]string{pkg.Replace, pkg.Provide} {
+				for name := range links {
+					name = strings.ToLower(name)
+					g.providers[name] = append(g.providers[name], key)
+				}
+			}
+		}
+	}
+
+	return g
+}
+
+// resolve returns the locked packages satisfying the requirement.
+func (g *lockGraph) resolve(name string) []string {
+	key := strings.ToLower(name)
+	if _, ok := g.packages[key]; ok {
+		return []string{key}
+	}
+	return g.providers[key]
+}
+
+// resolveAll returns the locked packages satisfying the requirements.
+func (g *lockGraph) resolveAll(require map[string]string) map[string]bool {
+	res := map[string]bool{}
+	for name := range require {
+		for _, key := range g.resolve(name) {
+			res[key] = true
+		}
+	}
+	return res
+}
+
+// reachable returns the locked packages needed by the requirements,
+// that is, the packages satisfying them and all their dependencies.
+//
+// As in composer, the require-dev of the dependencies is ignored.
+func (g *lockGraph) reachable(require map[string]string) map[string]bool {
+	visited := map[string]bool{}
+
+	var queue []string
+	for key := range g.resolveAll(require) {
+		queue = append(queue, key)
+	}
+
+	for len(queue) != 0 {
+		key := queue[0]
+		queue = queue[1:]
+		if visited[key] {
+			continue
+		}
+		visited[key] = true
+
+		for name := range g.packages[key].Require {
+			if IsPlatformPackage(name) {
+				continue
+			}
+			queue = append(queue, g.resolve(name)...)
+		}
+	}
+
+	return visited
+}
+
+// PruneDev returns a copy of the lock with the packages that
+// composer install --no-dev installs: the packages needed by the
+// require section of the config and their dependencies.
+//
+// The set is computed from the requirements rather than taken from
+// the packages section, so comparing it with Lock.Packages shows the
+// dev packages locked as production ones and the other way round.
+func (c *Config) PruneDev(lock *Lock) *Lock {
+	graph := newLockGraph(lock)
+	prod := graph.reachable(c.Require)
+
+	pruned := *lock
+	pruned.Packages = nil
+	pruned.PackagesDev = nil
+	pruned.PlatformDev = nil
+
+	for _, pkg := range lock.AllPackages() {
+		if prod[strings.ToLower(pkg.Name)] {
+			pruned.Packages = append(pruned.Packages, pkg)
+		}
+	}
+
+	sort.SliceStable(pruned.Packages, func(i, j int) bool {
+		return pruned.Packages[i].Name < pruned.Packages[j].Name
+	})
+
+	return &pruned
+}
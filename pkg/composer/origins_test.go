@@ -0,0 +1,75 @@
+package composer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPackageOrigins(t *testing.T) {
+	config := &Config{
+		Require:    map[string]string{"php": ">=8.1", "app/core": "^1.0", "psr/log-implementation": "*"},
+		RequireDev: map[string]string{"phpunit/phpunit": "^9.0", "app/util": "^1.0"},
+	}
+	lock := &Lock{
+		Packages: []LockPackage{
+			{Name: "app/core", Require: map[string]string{"php": ">=8.1", "app/util": "^1.0"}},
+			{Name: "app/util"},
+			{Name: "monolog/monolog", Provide: map[string]string{"psr/log-implementation": "3.0.0"}},
+			{Name: "old/stale"},
+		},
+		PackagesDev: []LockPackage{
+			{Name: "phpunit/phpunit", Require: map[string]string{"sebastian/diff": "^4.0"}, RequireDev: map[string]string{"app/dev-only": "*"}},
+			{Name: "sebastian/diff"},
+		},
+	}
+
+	expected := PackageOrigins{
+		"app/core":        OriginDirect,
+		"app/util":        OriginTransitive,
+		"monolog/monolog": OriginDirect,
+		"old/stale":       OriginUnused,
+		"phpunit/phpunit": OriginDirectDev,
+		"sebastian/diff":  OriginTransitiveDev,
+	}
+	origins := config.PackageOrigins(lock)
+	if !reflect.DeepEqual(origins, expected) {
+		t.Errorf("mismatch origins:\nwant: %v\nhave: %v", expected, origins)
+	}
+
+	devOnly := origins.DevOnlyPackages()
+	if !reflect.DeepEqual(devOnly, []string{"phpunit/phpunit", "sebastian/diff"}) {
+		t.Errorf("unexpected dev-only packages: %v", devOnly)
+	}
+}
+
+func TestPruneDev(t *testing.T) {
+	config := &Config{
+		Require:    map[string]string{"app/core": "^1.0"},
+		RequireDev: map[string]string{"phpunit/phpunit": "^9.0"},
+	}
+	lock := &Lock{
+		Packages: []LockPackage{
+			{Name: "app/core", Require: map[string]string{"psr/log": "^3.0"}},
+			{Name: "old/stale"},
+		},
+		PackagesDev: []LockPackage{
+			{Name: "phpunit/phpunit"},
+			// Locked as a dev package by mistake.
+			{Name: "psr/log"},
+		},
+		PlatformDev: LockPlatform{"ext-xdebug": "*"},
+	}
+
+	pruned := config.PruneDev(lock)
+
+	var names []string
+	for _, pkg := range pruned.Packages {
+		names = append(names, pkg.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"app/core", "psr/log"}) {
+		t.Errorf("unexpected production packages: %v", names)
+	}
+	if len(pruned.PackagesDev) != 0 || len(pruned.PlatformDev) != 0 || len(lock.PackagesDev) != 2 {
+		t.Errorf("expected only the copy to be pruned")
+	}
+}
@@ -0,0 +1,144 @@
+package composer
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/i582/go-composer.json/internal/version"
+)
+
+// CodeUnpinnedRequirement is the code of the errors
+// reported by CheckPinnedRequirements.
+const CodeUnpinnedRequirement = "unpinned-requirement"
+
+// CodeMutableDist is the code of the errors reported by CheckPinnedDists.
+const CodeMutableDist = "mutable-dist"
+
+// commitHash matches a commit hash, full or abbreviated.
+var commitHash = regexp.MustCompile(`^[0-9a-fA-F]{7,40}$`)
+
+// distReference matches the reference in the dist URLs of GitHub
+// (/zipball/<ref>), GitLab (/archive.zip?sha=<ref>) and Bitbucket
+// (/get/<ref>.zip).
+var distReference = regexp.MustCompile(`/(?:zipball|tarball|legacy\.zip|legacy\.tar\.gz)/([^/?#]+)|[?&]sha=([^&#]+)|/get/([^/?#]+?)\.(?:zip|tar\.gz|tar\.bz2)(?:$|[?#])`)
+
+// commitReference matches a commit hash reference in constraints
+// like dev-master#2eb0c0978d290a1c45346a1955188929cb4e5db7.
+var commitReference = regexp.MustCompile(`#[0-9a-fA-F]{7,40}$`)
+
+// IsPinnedConstraint reports whether the constraint refers to an
+// immutable state of the package.
+//
+// Constraints allowing branches (dev-master, 1.0.x-dev) or, with the
+// @dev stability flag, the dev versions of branches are mutable, unless
+// they are pinned to a commit with the #<hash> suffix. All other
+// constraints refer to tagged releases. Invalid constraints are
+// considered pinned, they are reported by the other checks.
+func IsPinnedConstraint(constraint string) bool {
+	constraint = strings.TrimSpace(constraint)
+
+	parsed, err := version.NewConstraint(constraint)
+	if err != nil {
+		return true
+	}
+
+	stability, flagged := parsed.StabilityFlag()
+	isMutable := len(parsed.Branches()) != 0 || flagged && stability == version.StabilityDev
+	if !isMutable {
+		return true
+	}
+
+	return commitReference.MatchString(constraint)
+}
+
+// CheckPinnedRequirements is a check provider function that reports
+// each requirement on a branch or dev versions without a pinned
+// commit reference, see IsPinnedConstraint.
+//
+// Supply-chain policies often require every dependency to refer to an
+// immutable state, and a branch can change at any time.
+//
+// See CheckProviderFunc
+func CheckPinnedRequirements(c *Config) []*ConfigError {
+	var errors []*ConfigError
+
+	for _, section := range []struct {
+		name    string
+		require map[string]string
+	}{
+		{name: "require", require: c.Require},
+		{name: "require-dev", require: c.RequireDev},
+	} {
+		for _, name := range sortedKeys(section.require) {
+			constraint := section.require[name]
+			if IsPinnedConstraint(constraint) {
+				continue
+			}
+
+			errors = append(errors, &ConfigError{
+				Msg:      fmt.Sprintf("%s: requirement on a branch or dev versions must be pinned to a commit with #<hash>: %s (%s)", section.name, name, constraint),
+				Critical: false,
+				Code:     CodeUnpinnedRequirement,
+				Pointer:  "/" + section.name + "/" + escapePointer(name),
+			})
+		}
+	}
+
+	return errors
+}
+
+// IsMutableDist reports whether the dist URL points at a mutable
+// branch or archive rather than at a commit.
+//
+// The references in the known archive URLs of GitHub, GitLab and
+// Bitbucket must be commit hashes, and URLs referring to branches
+// with refs/heads are always mutable. Other URLs are not recognized
+// and considered immutable.
+func IsMutableDist(url string) bool {
+	if strings.Contains(url, "/refs/heads/") {
+		return true
+	}
+
+	m := distReference.FindStringSubmatch(url)
+	if m == nil {
+		return false
+	}
+	for _, ref := range m[1:] {
+		if ref != "" {
+			return !commitHash.MatchString(ref)
+		}
+	}
+	return false
+}
+
+// CheckPinnedDists is a ContextCheck reporting each locked package
+// whose dist URL points at a mutable branch or archive, see IsMutableDist,
+// so the contents of the package can change without a change of the lock.
+//
+// Packages with the checksum of the dist are not reported, since composer
+// refuses to install a changed archive. The config without a lock is not
+// checked.
+//
+// See Config.RegisterCheck
+func CheckPinnedDists(ctx *CheckContext) []*ConfigError {
+	lock, err := ctx.Lock()
+	if err != nil {
+		return nil
+	}
+
+	var errors []*ConfigError
+	for _, pkg := range lock.AllPackages() {
+		if pkg.Dist == nil || pkg.Dist.Shasum != "" || !IsMutableDist(pkg.Dist.Url) {
+			continue
+		}
+
+		errors = append(errors, &ConfigError{
+			Msg:      fmt.Sprintf("composer.lock: dist of %s points at a mutable branch or archive: %s", pkg.Name, pkg.Dist.Url),
+			Critical: false,
+			Code:     CodeMutableDist,
+		})
+	}
+
+	return errors
+}
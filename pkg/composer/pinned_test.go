@@ -0,0 +1,103 @@
+package composer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIsPinnedConstraint(t *testing.T) {
+	tests := []struct {
+		Constraint string
+		Expected   bool
+	}{
+		{Constraint: "^1.0", Expected: true},
+		{Constraint: "1.0.0", Expected: true},
+		{Constraint: "dev-master", Expected: false},
+		{Constraint: "1.0.x-dev", Expected: false},
+		{Constraint: "@dev", Expected: false},
+		{Constraint: "*@dev", Expected: false},
+		{Constraint: "^1.0@dev", Expected: false},
+		{Constraint: "^1.0@beta", Expected: true},
+		{Constraint: "~1.0 || dev-main", Expected: false},
+		{Constraint: "dev-feature/login", Expected: false},
+		{Constraint: "dev-master#2eb0c0978d290a1c45346a1955188929cb4e5db7", Expected: true},
+		{Constraint: "dev-master#2eb0c09", Expected: true},
+		{Constraint: "dev-master#main", Expected: false},
+	}
+
+	for _, test := range tests {
+		if res := IsPinnedConstraint(test.Constraint); res != test.Expected {
+			t.Errorf("%s: expected %v, got %v", test.Constraint, test.Expected, res)
+		}
+	}
+}
+
+func TestCheckPinnedRequirements(t *testing.T) {
+	config := &Config{
+		Require:    map[string]string{"foo/bar": "dev-master", "foo/baz": "^1.0"},
+		RequireDev: map[string]string{"foo/qux": "1.0.x-dev"},
+	}
+
+	errs := CheckPinnedRequirements(config)
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %v", errs)
+	}
+	if errs[0].Pointer != "/require/foo~1bar" || errs[1].Pointer != "/require-dev/foo~1qux" {
+		t.Errorf("unexpected pointers: %s, %s", errs[0].Pointer, errs[1].Pointer)
+	}
+}
+
+func TestIsMutableDist(t *testing.T) {
+	for url, expected := range map[string]bool{
+		"https://api.github.com/repos/php-fig/log/zipball/fe5ea303b0887d5caefd3d431c3e61ad47037001": false,
+		"https://api.github.com/repos/php-fig/log/zipball/main":                                     true,
+		"https://api.github.com/repos/php-fig/log/tarball/fe5ea30":                                  false,
+		"https://github.com/php-fig/log/archive/refs/heads/master.zip":                              true,
+		"https://gitlab.com/api/v4/projects/my%2Flib/repository/archive.zip?sha=develop":            true,
+		"https://gitlab.com/api/v4/projects/my%2Flib/repository/archive.zip?sha=2eb0c0978d290a1c":   false,
+		"https://bitbucket.org/my/lib/get/master.zip":                                               true,
+		"https://bitbucket.org/my/lib/get/2eb0c0978d29.tar.gz":                                      false,
+		"https://repo.packagist.com/my/lib/1.0.0.zip":                                               false,
+	} {
+		if res := IsMutableDist(url); res != expected {
+			t.Errorf("%s: expected %v, got %v", url, expected, res)
+		}
+	}
+}
+
+func TestCheckPinnedDists(t *testing.T) {
+	dir, err := ioutil.TempDir("", "composer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	lock := `{
+		"packages": [
+			{"name": "my/branch", "dist": {"type": "zip", "url": "https://api.github.com/repos/my/branch/zipball/main"}},
+			{"name": "my/checked", "dist": {"type": "zip", "url": "https://bitbucket.org/my/checked/get/master.zip", "shasum": "0a1b2c"}},
+			{"name": "my/tag", "dist": {"type": "zip", "url": "https://api.github.com/repos/my/tag/zipball/2eb0c0978d290a1c45346a1955188929cb4e5db7"}},
+			{"name": "my/source", "source": {"type": "git", "url": "https://github.com/my/source.git", "reference": "main"}}
+		],
+		"packages-dev": [
+			{"name": "my/dev", "dist": {"type": "zip", "url": "https://github.com/my/dev/archive/refs/heads/develop.zip"}}
+		]
+	}`
+	if err := ioutil.WriteFile(filepath.Join(dir, "composer.lock"), []byte(lock), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	errs := CheckPinnedDists(newCheckContext(&Config{Path: filepath.Join(dir, "composer.json"), RootDir: dir}))
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %v", errs)
+	}
+	if errs[0].Code != CodeMutableDist || errs[0].Msg != "composer.lock: dist of my/branch points at a mutable branch or archive: https://api.github.com/repos/my/branch/zipball/main" {
+		t.Errorf("unexpected error: %v", errs[0])
+	}
+
+	if errs := CheckPinnedDists(newCheckContext(&Config{Path: filepath.Join(dir, "missing", "composer.json")})); len(errs) != 0 {
+		t.Errorf("expected no errors without a lock, got %v", errs)
+	}
+}
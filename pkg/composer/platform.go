@@ -0,0 +1,195 @@
+package composer
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/i582/go-composer.json/internal/version"
+)
+
+// CodePlatformOverride is the code of the errors reported
+// by CheckPlatformOverrides.
+const CodePlatformOverride = "platform-override"
+
+// platformPackageRegexp matches the names of the platform packages,
+// as composer's PlatformRepository::PLATFORM_PACKAGE_REGEX does.
+var platformPackageRegexp = regexp.MustCompile(`(?i)^(?:php(?:-64bit|-ipv6|-zts|-debug)?|hhvm|(?:ext|lib)-[a-z0-9](?:[_.-]?[a-z0-9]+)*|composer(?:-(?:plugin|runtime))?-api)$`)
+
+// IsPlatformPackage reports whether the package is a platform package,
+// that is, PHP itself, an extension or a system library, rather than
+// a package installed by composer.
+//
+// Example:
+//
+//	php, php-64bit, hhvm, ext-mbstring, lib-icu, composer-plugin-api
+func IsPlatformPackage(name string) bool {
+	return platformPackageRegexp.MatchString(name)
+}
+
+// PlatformRequires returns the requirements of the require
+// section on platform packages, see IsPlatformPackage.
+func (c *Config) PlatformRequires() map[string]string {
+	res := map[string]string{}
+	for name, constraint := range c.Require {
+		if IsPlatformPackage(name) {
+			res[name] = constraint
+		}
+	}
+	return res
+}
+
+// PackageRequires returns the requirements of the require
+// section on packages installed by composer, that is,
+// all requirements except the platform ones.
+func (c *Config) PackageRequires() map[string]string {
+	res := map[string]string{}
+	for name, constraint := range c.Require {
+		if !IsPlatformPackage(name) {
+			res[name] = constraint
+		}
+	}
+	return res
+}
+
+// PlatformOverrides is the value of the config.platform setting,
+// the versions of the platform packages composer pretends to have
+// instead of the ones of the current runtime.
+//
+// In composer.json a package can also be set to false to pretend
+// it is not installed, such packages are stored with an empty version.
+//
+// Example:
+//
+//	"platform": {"php": "7.0.3", "ext-something": "4.0.3", "ext-xdebug": false}
+type PlatformOverrides map[string]string
+
+// UnmarshalJSON implements the json.Unmarshaler interface
+// for both the version and the false values.
+func (p *PlatformOverrides) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("platform must be an object")
+	}
+
+	overrides := make(PlatformOverrides, len(raw))
+	for name, value := range raw {
+		var ver string
+		if err := json.Unmarshal(value, &ver); err == nil {
+			overrides[name] = ver
+			continue
+		}
+
+		var enabled bool
+		if err := json.Unmarshal(value, &enabled); err != nil || enabled {
+			return fmt.Errorf("platform package %q must be a version or false", name)
+		}
+		overrides[name] = ""
+	}
+
+	*p = overrides
+	return nil
+}
+
+// PlatformOverride returns the version of the platform package set in
+// config.platform. The disabled flag is true if the package is set to
+// false, ok is false if the package is not overridden.
+func (c *Config) PlatformOverride(name string) (ver string, disabled bool, ok bool) {
+	ver, ok = c.Settings.Platform[name]
+	return ver, ok && ver == "", ok
+}
+
+// CheckPlatformOverrides is a check for Config.AddCheckProvider that
+// reports the config.platform overrides that mask the requirements of
+// the config: invalid versions, versions that do not satisfy the
+// constraint in require, and required packages set to false. As in
+// Platform.Check, constraints that cannot be parsed are reported too.
+//
+// Composer resolves the dependencies against the overrides only,
+// so such configs install on a runtime that cannot run them.
+//
+// See CheckProviderFunc
+func CheckPlatformOverrides(c *Config) []*ConfigError {
+	var errors []*ConfigError
+	for _, name := range sortedKeys(c.Settings.Platform) {
+		msg := c.checkPlatformOverride(name, c.Settings.Platform[name])
+		if msg == "" {
+			continue
+		}
+
+		errors = append(errors, &ConfigError{
+			Msg:      msg,
+			Critical: false,
+			Code:     CodePlatformOverride,
+			Pointer:  "/config/platform/" + escapePointer(name),
+		})
+	}
+
+	return errors
+}
+
+func (c *Config) checkPlatformOverride(name, override string) string {
+	required, isRequired := c.Require[name]
+
+	if override == "" {
+		if isRequired {
+			return fmt.Sprintf("platform package '%s' is required but disabled in config.platform", name)
+		}
+		return ""
+	}
+
+	ver, err := parsePlatformVersion(override)
+	if err != nil {
+		return fmt.Sprintf("config.platform version '%s' of '%s' is invalid: %v", override, name, err)
+	}
+
+	if !isRequired {
+		return ""
+	}
+
+	// Reported as in Platform.Check, so that both checks agree.
+	constraint, err := version.NewConstraint(required)
+	if err != nil {
+		return fmt.Sprintf("constraint '%s' of platform package '%s' cannot be checked: %v", required, name, err)
+	}
+	if constraint.Allows(ver) {
+		return ""
+	}
+
+	return fmt.Sprintf("config.platform version '%s' of '%s' does not satisfy the required constraint '%s'", override, name, required)
+}
+
+// parsePlatformVersion parses the version of a platform package,
+// which may be partial (8.1) or have four parts (1.0.2.1).
+func parsePlatformVersion(raw string) (*version.Version, error) {
+	normalized, err := version.Normalize(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.SplitN(normalized, "-", 2)
+	numbers := strings.Split(parts[0], ".")
+	if len(numbers) < 3 {
+		return nil, fmt.Errorf("version '%s' is not numeric", raw)
+	}
+
+	val := strings.Join(numbers[:3], ".")
+	if len(parts) == 2 {
+		val += "-" + parts[1]
+	}
+
+	ver, err := version.NewVersion(val)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(numbers) == 4 {
+		ver.Build, err = strconv.ParseInt(numbers[3], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("part 4 ('%s') of the version must be a number", numbers[3])
+		}
+	}
+	return ver, nil
+}
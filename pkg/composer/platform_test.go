@@ -0,0 +1,88 @@
+package composer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCheckPlatformOverrides(t *testing.T) {
+	config, errs := NewConfigFromData([]byte(`{
+		"version": "1.0.0",
+		"require": {"php": "^8.1", "ext-intl": "*", "ext-json": "*", "lib-openssl": ">=1.1.1.21"},
+		"config": {
+			"platform": {"php": "7.4.0", "ext-intl": false, "ext-json": "1.7", "ext-mbstring": "x.y", "lib-openssl": "1.1.1.20"}
+		}
+	}`), "composer.json")
+	if errs != nil {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	if ver, disabled, ok := config.PlatformOverride("ext-intl"); !ok || !disabled || ver != "" {
+		t.Errorf("expected ext-intl to be disabled")
+	}
+	if ver, disabled, ok := config.PlatformOverride("php"); !ok || disabled || ver != "7.4.0" {
+		t.Errorf("unexpected php override: %q", ver)
+	}
+
+	var pointers []string
+	for _, err := range CheckPlatformOverrides(config) {
+		pointers = append(pointers, err.Pointer)
+	}
+
+	expected := []string{
+		"/config/platform/ext-intl",
+		"/config/platform/ext-mbstring",
+		"/config/platform/lib-openssl",
+		"/config/platform/php",
+	}
+	if !reflect.DeepEqual(pointers, expected) {
+		t.Errorf("mismatch pointers:\nwant: %v\nhave: %v", expected, pointers)
+	}
+
+	config, _ = NewConfigFromData([]byte(`{
+		"version": "1.0.0",
+		"require": {"php": "^8.1 ||| ^9.0"},
+		"config": {"platform": {"php": "8.1.0"}}
+	}`), "composer.json")
+	overrideErrs := CheckPlatformOverrides(config)
+	if len(overrideErrs) != 1 || overrideErrs[0].Pointer != "/config/platform/php" || !strings.Contains(overrideErrs[0].Msg, "cannot be checked") {
+		t.Errorf("expected the unparsable constraint to be reported, got %v", overrideErrs)
+	}
+}
+
+func TestPlatformRequires(t *testing.T) {
+	config, _ := NewConfigFromData([]byte(`{
+		"version": "1.0.0",
+		"require": {
+			"php": ">=8.1",
+			"php-64bit": "*",
+			"ext-mbstring": "*",
+			"ext-pdo_mysql": "*",
+			"lib-icu": ">=60",
+			"composer-plugin-api": "^2.0",
+			"monolog/monolog": "^3.0",
+			"extension/package": "^1.0"
+		}
+	}`), "composer.json")
+
+	expectedPlatform := map[string]string{
+		"php":                 ">=8.1",
+		"php-64bit":           "*",
+		"ext-mbstring":        "*",
+		"ext-pdo_mysql":       "*",
+		"lib-icu":             ">=60",
+		"composer-plugin-api": "^2.0",
+	}
+	if platform := config.PlatformRequires(); !reflect.DeepEqual(platform, expectedPlatform) {
+		t.Errorf("mismatch platform requires:\nwant: %v\nhave: %v", expectedPlatform, platform)
+	}
+
+	expectedPackages := map[string]string{
+		"monolog/monolog":   "^3.0",
+		"extension/package": "^1.0",
+	}
+	if packages := config.PackageRequires(); !reflect.DeepEqual(packages, expectedPackages) {
+		t.Errorf("mismatch package requires:\nwant: %v\nhave: %v", expectedPackages, packages)
+	}
+}
@@ -0,0 +1,12 @@
+package composer
+
+import (
+	"strings"
+)
+
+// escapePointer escapes a key to be used as a JSON pointer
+// reference token as described in RFC 6901.
+func escapePointer(key string) string {
+	key = strings.ReplaceAll(key, "~", "~0")
+	return strings.ReplaceAll(key, "/", "~1")
+}
@@ -0,0 +1,89 @@
+package composer
+
+import (
+	"fmt"
+
+	"github.com/i582/go-composer.json/internal/version"
+)
+
+// CodeRedundantPolyfill is the code of the errors reported by CheckPolyfills.
+const CodeRedundantPolyfill = "redundant-polyfill"
+
+// polyfills maps the polyfill packages to the PHP version
+// in which their functionality is native.
+var polyfills = map[string]string{
+	"symfony/polyfill-php54":  "5.4",
+	"symfony/polyfill-php55":  "5.5",
+	"symfony/polyfill-php56":  "5.6",
+	"symfony/polyfill-php70":  "7.0",
+	"symfony/polyfill-php71":  "7.1",
+	"symfony/polyfill-php72":  "7.2",
+	"symfony/polyfill-php73":  "7.3",
+	"symfony/polyfill-php74":  "7.4",
+	"symfony/polyfill-php80":  "8.0",
+	"symfony/polyfill-php81":  "8.1",
+	"symfony/polyfill-php82":  "8.2",
+	"symfony/polyfill-php83":  "8.3",
+	"symfony/polyfill-php84":  "8.4",
+	"symfony/polyfill-php85":  "8.5",
+	"paragonie/random_compat": "7.0",
+	// The sodium extension is bundled with PHP since 7.2.
+	"paragonie/sodium_compat": "7.2",
+}
+
+// CheckPolyfills is a check for Config.AddCheckProvider that reports
+// required polyfills whose functionality is native in the minimum
+// PHP version allowed by the php requirement.
+//
+// Such polyfills can be removed, and if they are installed as
+// dependencies of other packages, listed in the replace section
+// with the "*" constraint to prevent the installation.
+//
+// See CheckProviderFunc
+func CheckPolyfills(c *Config) []*ConfigError {
+	php, ok := c.Require["php"]
+	if !ok {
+		return nil
+	}
+
+	constraint, err := ParseConstraint(php)
+	if err != nil {
+		return nil
+	}
+
+	minimum, ok := constraint.LowerBound()
+	if !ok {
+		return nil
+	}
+
+	var errors []*ConfigError
+	for _, section := range []struct {
+		name    string
+		require map[string]string
+	}{
+		{name: "require", require: c.Require},
+		{name: "require-dev", require: c.RequireDev},
+	} {
+		for _, name := range sortedKeys(section.require) {
+			native, ok := polyfills[name]
+			if !ok {
+				continue
+			}
+
+			nativeVersion, err := version.NewVersion(native + ".0-dev")
+			if err != nil || minimum.Compare(nativeVersion) < 0 {
+				continue
+			}
+
+			errors = append(errors, &ConfigError{
+				Msg: fmt.Sprintf("%s: package %s is redundant, its functionality is native since PHP %s and the required PHP version is '%s', remove it or add it to replace with '*'",
+					section.name, name, native, php),
+				Critical: false,
+				Code:     CodeRedundantPolyfill,
+				Pointer:  "/" + section.name + "/" + escapePointer(name),
+			})
+		}
+	}
+
+	return errors
+}
@@ -0,0 +1,39 @@
+package composer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCheckPolyfills(t *testing.T) {
+	config := &Config{
+		Require: map[string]string{
+			"php":                     "^7.4 || ^8.0",
+			"symfony/polyfill-php73":  "^1.0",
+			"symfony/polyfill-php80":  "^1.0",
+			"paragonie/random_compat": "^9.0",
+			"paragonie/sodium_compat": "^1.20",
+		},
+		RequireDev: map[string]string{"symfony/polyfill-php74": "^1.0"},
+	}
+
+	var pointers []string
+	for _, err := range CheckPolyfills(config) {
+		pointers = append(pointers, err.Pointer)
+	}
+
+	expected := []string{
+		"/require/paragonie~1random_compat",
+		"/require/paragonie~1sodium_compat",
+		"/require/symfony~1polyfill-php73",
+		"/require-dev/symfony~1polyfill-php74",
+	}
+	if !reflect.DeepEqual(pointers, expected) {
+		t.Errorf("mismatch pointers:\nwant: %v\nhave: %v", expected, pointers)
+	}
+
+	config.Require["php"] = "<8.0"
+	if errs := CheckPolyfills(config); len(errs) != 0 {
+		t.Errorf("expected no errors without a lower limit, got %v", errs)
+	}
+}
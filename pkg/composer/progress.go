@@ -0,0 +1,45 @@
+package composer
+
+import (
+	"context"
+)
+
+// ProgressReporter receives the progress of the long operations,
+// such as the classmap generation, to show it in spinners or CI logs.
+//
+// The reporter is passed to the operations with the context,
+// see ContextWithProgress.
+type ProgressReporter interface {
+	// Progress is called when the operation starts the step and after each
+	// item of the step is done, so done goes from 0 to total.
+	Progress(step string, done, total int)
+}
+
+// ProgressFunc is an adapter to use ordinary functions as ProgressReporter.
+//
+// Example:
+//
+//	ctx = composer.ContextWithProgress(ctx, composer.ProgressFunc(func(step string, done, total int) {
+//	  log.Printf("%s: %d/%d", step, done, total)
+//	}))
+type ProgressFunc func(step string, done, total int)
+
+// Progress calls f(step, done, total).
+func (f ProgressFunc) Progress(step string, done, total int) {
+	f(step, done, total)
+}
+
+type progressKey struct{}
+
+// ContextWithProgress returns a copy of the context
+// that passes the reporter to the operations.
+func ContextWithProgress(ctx context.Context, reporter ProgressReporter) context.Context {
+	return context.WithValue(ctx, progressKey{}, reporter)
+}
+
+// reportProgress passes the progress to the reporter of the context, if any.
+func reportProgress(ctx context.Context, step string, done, total int) {
+	if reporter, ok := ctx.Value(progressKey{}).(ProgressReporter); ok {
+		reporter.Progress(step, done, total)
+	}
+}
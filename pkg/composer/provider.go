@@ -0,0 +1,150 @@
+package composer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// CodeExternalCheckFailed is the code of the errors reported when
+// an external check provider cannot be run.
+const CodeExternalCheckFailed = "external-check-failed"
+
+// DefaultExternalCheckTimeout is the time an external check
+// provider is given to finish if its Timeout is not set.
+const DefaultExternalCheckTimeout = 30 * time.Second
+
+// CheckProvider is a source of custom checks that can
+// report several errors at once.
+//
+// See Config.AddCheckProvider
+type CheckProvider interface {
+	Check(*Config) []*ConfigError
+}
+
+// CheckProviderFunc is an adapter to allow the use of ordinary
+// functions as check providers.
+//
+// Example:
+//
+//	cfg.AddCheckProvider(composer.CheckProviderFunc(composer.CheckKeywords))
+type CheckProviderFunc func(*Config) []*ConfigError
+
+// Check implements the CheckProvider interface.
+func (f CheckProviderFunc) Check(c *Config) []*ConfigError {
+	return f(c)
+}
+
+// ExternalCheckProvider runs the checks in a separate process.
+//
+// This allows shipping checks without forking this library and
+// writing them in any language. The protocol is JSON over stdio:
+// the request is written to the stdin of the process, and the
+// process must write the response to stdout and exit with code 0.
+//
+// Request:
+//
+//	{"path": "/path/to/composer.json", "config": {...content of composer.json...}}
+//
+// Response:
+//
+//	{"errors": [{"message": "...", "critical": false, "code": "my-rule", "pointer": "/name"}]}
+type ExternalCheckProvider struct {
+	// Command is the executable to run.
+	Command string
+	// Args are the arguments passed to the command.
+	Args []string
+	// Timeout is the time the process is given to finish, after
+	// which it is killed, DefaultExternalCheckTimeout if not set.
+	Timeout time.Duration
+}
+
+// externalRequest is the request sent to an external check provider.
+type externalRequest struct {
+	Path   string          `json:"path"`
+	Config json.RawMessage `json:"config"`
+}
+
+// externalResponse is the response of an external check provider.
+type externalResponse struct {
+	Errors []struct {
+		Message  string `json:"message"`
+		Critical bool   `json:"critical"`
+		Code     string `json:"code"`
+		Pointer  string `json:"pointer"`
+	} `json:"errors"`
+}
+
+// Check implements the CheckProvider interface.
+//
+// If the process cannot be run, times out or returns an invalid
+// response, a single critical error with the CodeExternalCheckFailed
+// code is returned. The process is not run for read-only configs,
+// see LoadOptions.ReadOnly.
+func (p *ExternalCheckProvider) Check(c *Config) []*ConfigError {
+	if c.ReadOnly {
+		return p.failed(fmt.Errorf("external checks are not run in read-only mode"))
+	}
+
+	raw := c.Raw
+	if len(raw) == 0 {
+		raw = []byte("{}")
+	}
+
+	req, err := json.Marshal(externalRequest{Path: c.Path, Config: raw})
+	if err != nil {
+		return p.failed(err)
+	}
+
+	timeout := p.Timeout
+	if timeout <= 0 {
+		timeout = DefaultExternalCheckTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.Command, p.Args...)
+	cmd.Stdin = bytes.NewReader(req)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return p.failed(fmt.Errorf("timed out after %v", timeout))
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			err = fmt.Errorf("%v: %s", err, msg)
+		}
+		return p.failed(err)
+	}
+
+	var resp externalResponse
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		return p.failed(fmt.Errorf("invalid response: %v", err))
+	}
+
+	errors := make([]*ConfigError, 0, len(resp.Errors))
+	for _, e := range resp.Errors {
+		errors = append(errors, &ConfigError{
+			Msg:      e.Message,
+			Critical: e.Critical,
+			Code:     e.Code,
+			Pointer:  e.Pointer,
+		})
+	}
+
+	return errors
+}
+
+func (p *ExternalCheckProvider) failed(err error) []*ConfigError {
+	return []*ConfigError{{
+		Msg:      fmt.Sprintf("external check %s: %v", p.Command, err),
+		Critical: true,
+		Code:     CodeExternalCheckFailed,
+	}}
+}
@@ -0,0 +1,52 @@
+package composer
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExternalCheckProvider(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+
+	config, _ := NewConfigFromData([]byte(`{"name": "my/package", "version": "1.0.0"}`), "composer.json")
+	config.AddCheckProvider(&ExternalCheckProvider{
+		Command: "sh",
+		Args: []string{"-c", `grep -q '"my/package"' && ` +
+			`echo '{"errors": [{"message": "first"}, {"message": "second", "critical": true, "code": "my-rule"}]}'`},
+	})
+	config.AddCheckProvider(&ExternalCheckProvider{
+		Command: "sh",
+		Args:    []string{"-c", "echo broken"},
+	})
+
+	errs := config.CheckConfig()
+	if errs.Len() != 3 {
+		t.Fatalf("expected 3 errors, got %v", errs)
+	}
+	if errs.Errors[1].Code != "my-rule" || !errs.Errors[1].Critical {
+		t.Errorf("unexpected error: %+v", errs.Errors[1])
+	}
+	if errs.Errors[2].Code != CodeExternalCheckFailed {
+		t.Errorf("expected a failed provider error, got %+v", errs.Errors[2])
+	}
+
+	config, _ = NewConfigFromData([]byte(`{"name": "my/package", "version": "1.0.0"}`), "composer.json")
+	config.AddCheckProvider(&ExternalCheckProvider{
+		Command: "sh",
+		Args:    []string{"-c", "exec sleep 10"},
+		Timeout: 100 * time.Millisecond,
+	})
+
+	start := time.Now()
+	errs = config.CheckConfig()
+	if errs.Len() != 1 || !strings.Contains(errs.Errors[0].Msg, "timed out") {
+		t.Errorf("expected a timeout error, got %v", errs)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the process to be killed, took %v", elapsed)
+	}
+}
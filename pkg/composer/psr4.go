@@ -0,0 +1,66 @@
+package composer
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Psr4 is the psr-4 mapping of namespace prefixes to paths.
+//
+// In composer.json a prefix maps either to a single path or to an
+// array of paths, in the second case composer looks for the classes
+// in all of them. Both forms are stored as a list.
+//
+// Examples:
+//
+//	"Monolog\\": "src/"
+//	"Monolog\\": ["src/", "lib/"]
+type Psr4 map[string][]string
+
+// UnmarshalJSON implements the json.Unmarshaler interface
+// for both the string and the array forms of paths.
+func (p *Psr4) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("psr-4 must be an object")
+	}
+
+	psr4 := make(Psr4, len(raw))
+	for prefix, value := range raw {
+		var single string
+		if err := json.Unmarshal(value, &single); err == nil {
+			psr4[prefix] = []string{single}
+			continue
+		}
+
+		var list []string
+		if err := json.Unmarshal(value, &list); err != nil {
+			return fmt.Errorf("psr-4 path of %q must be a string or an array of strings", prefix)
+		}
+		psr4[prefix] = list
+	}
+
+	*p = psr4
+	return nil
+}
+
+// Path returns the first path of the prefix, for the code that
+// supports only a single path for a namespace.
+func (p Psr4) Path(prefix string) string {
+	paths := p[prefix]
+	if len(paths) == 0 {
+		return ""
+	}
+	return paths[0]
+}
+
+// Prefixes returns the namespace prefixes in sorted order.
+func (p Psr4) Prefixes() []string {
+	prefixes := make([]string, 0, len(p))
+	for prefix := range p {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+	return prefixes
+}
@@ -0,0 +1,36 @@
+package composer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPsr4Paths(t *testing.T) {
+	config, errs := NewConfigFromData([]byte(`{
+		"version": "1.0.0",
+		"autoload": {"psr-4": {"App\\": ["src/", "lib/"], "App\\Core\\": "core/", "": "fallback/"}}
+	}`), "service/composer.json")
+	if errs != nil {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	tests := []struct {
+		namespace string
+		paths     []string
+	}{
+		{namespace: `App\Http`, paths: []string{"service/src/", "service/lib/"}},
+		{namespace: `App\Core\Utils`, paths: []string{"service/core/"}},
+		{namespace: `Other`, paths: []string{"service/fallback/"}},
+	}
+
+	for _, tt := range tests {
+		paths, found := config.Psr4PathForNamespace(tt.namespace)
+		if !found || !reflect.DeepEqual(paths, tt.paths) {
+			t.Errorf("Psr4PathForNamespace(%q) = %v, want %v", tt.namespace, paths, tt.paths)
+		}
+	}
+
+	if path := config.Autoload.Psr4.Path(`App\`); path != "src/" {
+		t.Errorf("unexpected first path: %s", path)
+	}
+}
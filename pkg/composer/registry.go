@@ -0,0 +1,130 @@
+package composer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strings"
+)
+
+// CodeReservedNamespace is the code of the errors reported
+// by NamespaceRegistry.
+const CodeReservedNamespace = "reserved-namespace"
+
+// NamespaceRegistry is a registry of the namespace prefixes reserved
+// for packages or teams in a workspace. It is a check provider that
+// reports the psr-4 prefixes of the config that claim a namespace
+// reserved for someone else.
+//
+// The registry is usually kept in a central JSON file, see
+// LoadNamespaceRegistry. The owners are package names or patterns,
+// for example, acme/billing or acme-billing/* for all packages
+// of a team.
+//
+// Example:
+//
+//	{
+//	    "namespaces": {
+//	        "Acme\\Billing\\": "acme-billing/*",
+//	        "Acme\\Shared\\": "acme/shared"
+//	    }
+//	}
+type NamespaceRegistry struct {
+	// Namespaces maps the reserved namespace prefixes to the owners.
+	Namespaces map[string]string `json:"namespaces"`
+}
+
+// LoadNamespaceRegistry reads the registry from the JSON file.
+func LoadNamespaceRegistry(filename string) (*NamespaceRegistry, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var registry NamespaceRegistry
+	if err := json.Unmarshal(data, &registry); err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+
+	for prefix := range registry.Namespaces {
+		if !strings.HasSuffix(prefix, `\`) {
+			return nil, fmt.Errorf("%s: namespace %s must end with a namespace separator", filename, prefix)
+		}
+	}
+
+	return &registry, nil
+}
+
+// Owner returns the owner of the namespace, that is, the owner
+// of the longest reserved prefix of the namespace.
+//
+// As in PHP, namespaces are case-insensitive.
+func (r *NamespaceRegistry) Owner(namespace string) (owner string, prefix string, ok bool) {
+	namespace = strings.TrimSuffix(namespace, `\`) + `\`
+	for reserved, reservedOwner := range r.Namespaces {
+		if hasPrefixFold(namespace, reserved) && len(reserved) > len(prefix) {
+			owner, prefix, ok = reservedOwner, reserved, true
+		}
+	}
+	return owner, prefix, ok
+}
+
+// IsOwner reports whether the package matches the owner pattern,
+// package names are case-insensitive.
+func (r *NamespaceRegistry) IsOwner(pkg string, owner string) bool {
+	matched, err := path.Match(strings.ToLower(owner), strings.ToLower(pkg))
+	return err == nil && matched
+}
+
+// Check implements the CheckProvider interface.
+//
+// A psr-4 prefix is reported if it is inside a namespace reserved for
+// another owner, or if it claims a parent of such a namespace, since
+// then it can load the classes of that namespace as well.
+func (r *NamespaceRegistry) Check(c *Config) []*ConfigError {
+	var errors []*ConfigError
+	errors = append(errors, r.checkAutoload(c, "autoload", c.Autoload.Psr4)...)
+	errors = append(errors, r.checkAutoload(c, "autoload-dev", c.AutoloadDev.Psr4)...)
+	return errors
+}
+
+func (r *NamespaceRegistry) checkAutoload(c *Config, section string, psr4 Psr4) []*ConfigError {
+	var errors []*ConfigError
+	for _, prefix := range psr4.Prefixes() {
+		pointer := "/" + section + "/psr-4/" + escapePointer(prefix)
+
+		if owner, reserved, ok := r.Owner(prefix); ok && !r.IsOwner(c.Name, owner) {
+			errors = append(errors, &ConfigError{
+				Msg: fmt.Sprintf("%s: namespace %s is reserved for %s by %s",
+					section, prefix, owner, reserved),
+				Critical: false,
+				Code:     CodeReservedNamespace,
+				Pointer:  pointer,
+			})
+			continue
+		}
+
+		for _, reserved := range sortedKeys(r.Namespaces) {
+			owner := r.Namespaces[reserved]
+			if strings.EqualFold(reserved, prefix) || !hasPrefixFold(reserved, prefix) || r.IsOwner(c.Name, owner) {
+				continue
+			}
+
+			errors = append(errors, &ConfigError{
+				Msg: fmt.Sprintf("%s: namespace %s includes %s reserved for %s",
+					section, prefix, reserved, owner),
+				Critical: false,
+				Code:     CodeReservedNamespace,
+				Pointer:  pointer,
+			})
+		}
+	}
+	return errors
+}
+
+// hasPrefixFold reports whether the string begins with
+// the prefix, ignoring the case.
+func hasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
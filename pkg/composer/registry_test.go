@@ -0,0 +1,71 @@
+package composer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNamespaceRegistry(t *testing.T) {
+	dir, err := ioutil.TempDir("", "composer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	registryPath := filepath.Join(dir, "namespaces.json")
+	err = ioutil.WriteFile(registryPath, []byte(`{
+		"namespaces": {
+			"Acme\\Billing\\": "acme-billing/*",
+			"Acme\\Shared\\": "acme/shared"
+		}
+	}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	registry, err := LoadNamespaceRegistry(registryPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config, _ := NewConfigFromData([]byte(`{
+		"name": "acme-billing/invoices",
+		"version": "1.0.0",
+		"autoload": {"psr-4": {
+			"Acme\\Billing\\Invoices\\": "src/",
+			"Acme\\Shared\\Money\\": "money/",
+			"acme\\billing\\Legacy\\": "billing/",
+			"ACME\\SHARED\\Tools\\": "tools/",
+			"Acme\\": "legacy/"
+		}}
+	}`), "composer.json")
+
+	var actual []string
+	for _, err := range registry.Check(config) {
+		actual = append(actual, err.Pointer+" "+err.Msg)
+	}
+
+	expected := []string{
+		`/autoload/psr-4/ACME\SHARED\Tools\ autoload: namespace ACME\SHARED\Tools\ is reserved for acme/shared by Acme\Shared\`,
+		`/autoload/psr-4/Acme\ autoload: namespace Acme\ includes Acme\Shared\ reserved for acme/shared`,
+		`/autoload/psr-4/Acme\Shared\Money\ autoload: namespace Acme\Shared\Money\ is reserved for acme/shared by Acme\Shared\`,
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("mismatch errors:\nwant: %v\nhave: %v", strings.Join(expected, "\n"), strings.Join(actual, "\n"))
+	}
+
+	if !registry.IsOwner("Acme-Billing/Invoices", "acme-billing/*") {
+		t.Errorf("expected package names to be matched case-insensitively")
+	}
+
+	if err := ioutil.WriteFile(registryPath, []byte(`{"namespaces": {"Acme": "acme/*"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadNamespaceRegistry(registryPath); err == nil {
+		t.Errorf("expected error for a prefix without a namespace separator")
+	}
+}
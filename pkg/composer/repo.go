@@ -0,0 +1,238 @@
+package composer
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// RepoOptions are the options of a composer repository, passed
+// by composer to the PHP stream context of the requests.
+//
+// Example:
+//
+//	"options": {
+//	    "ssl": {"verify_peer": true, "cafile": "/path/to/ca.pem"},
+//	    "http": {"header": ["API-TOKEN: YOUR-API-TOKEN"]}
+//	}
+type RepoOptions struct {
+	SSL  RepoSSLOptions  `json:"ssl"`
+	HTTP RepoHTTPOptions `json:"http"`
+}
+
+// RepoSSLOptions are the ssl context options of a repository.
+//
+// Flags are pointers, since an unset flag means
+// the default of PHP rather than false.
+type RepoSSLOptions struct {
+	// Require verification of the SSL certificate used.
+	VerifyPeer *bool `json:"verify_peer"`
+	// Require verification of the peer name.
+	VerifyPeerName *bool `json:"verify_peer_name"`
+	// Allow self-signed certificates, requires verify_peer.
+	AllowSelfSigned *bool `json:"allow_self_signed"`
+	// Location of Certificate Authority file on local filesystem.
+	Cafile string `json:"cafile"`
+	// The dir with the hashed Certificate Authority files.
+	Capath string `json:"capath"`
+	// Path to the local certificate file on filesystem.
+	LocalCert string `json:"local_cert"`
+	// Path to the local private key file on filesystem.
+	LocalPk string `json:"local_pk"`
+	// Passphrase with which the local_cert file was encoded.
+	Passphrase string `json:"passphrase"`
+	// The peer name to be used, by default the host of the url.
+	PeerName string `json:"peer_name"`
+}
+
+// RepoHTTPOptions are the http context options of a repository.
+type RepoHTTPOptions struct {
+	// Additional headers to be sent with the requests,
+	// for example, "API-TOKEN: YOUR-API-TOKEN".
+	Header RepoHeaders `json:"header"`
+	// Read timeout in seconds.
+	Timeout float64 `json:"timeout"`
+	// The proxy server, for example, tcp://proxy.example.com:5100.
+	Proxy string `json:"proxy"`
+}
+
+// RepoHeaders are the headers of the http context options.
+//
+// As in PHP, the headers may be given as an array or as a single
+// string, where several headers are separated by line breaks.
+type RepoHeaders []string
+
+// UnmarshalJSON implements the json.Unmarshaler interface
+// for both the string and the array forms.
+func (h *RepoHeaders) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		headers := RepoHeaders{}
+		for _, line := range strings.Split(strings.ReplaceAll(single, "\r\n", "\n"), "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				headers = append(headers, line)
+			}
+		}
+		*h = headers
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("header must be a string or an array of strings")
+	}
+
+	*h = list
+	return nil
+}
+
+// IsComposer reports whether the repository is
+// a composer repository, such as packagist.org.
+func (c *ConfigRepo) IsComposer() bool {
+	return c.Type == "composer"
+}
+
+// RepoKind is the kind of a repository, several repository
+// types may be of the same kind, for example, git and github.
+type RepoKind string
+
+// The kinds of repositories.
+const (
+	RepoKindUnknown  RepoKind = ""
+	RepoKindComposer RepoKind = "composer"
+	RepoKindVcs      RepoKind = "vcs"
+	RepoKindPath     RepoKind = "path"
+	RepoKindPackage  RepoKind = "package"
+	RepoKindArtifact RepoKind = "artifact"
+)
+
+// vcsTypes are the types of the VCS repositories.
+var vcsTypes = map[string]bool{
+	"vcs":           true,
+	"git":           true,
+	"github":        true,
+	"gitlab":        true,
+	"bitbucket":     true,
+	"git-bitbucket": true,
+	"hg":            true,
+	"hg-bitbucket":  true,
+	"svn":           true,
+	"fossil":        true,
+	"perforce":      true,
+}
+
+// Kind returns the kind of the repository by its type.
+func (c *ConfigRepo) Kind() RepoKind {
+	switch {
+	case vcsTypes[c.Type]:
+		return RepoKindVcs
+	case c.Type == "composer":
+		return RepoKindComposer
+	case c.Type == "path":
+		return RepoKindPath
+	case c.Type == "package":
+		return RepoKindPackage
+	case c.Type == "artifact":
+		return RepoKindArtifact
+	}
+	return RepoKindUnknown
+}
+
+// VcsType returns the VCS driver of the repository. For the generic
+// vcs type, the driver is guessed from the url as composer does,
+// git is returned if it cannot be guessed.
+//
+// An empty string is returned for repositories of other kinds.
+func (c *ConfigRepo) VcsType() string {
+	if c.Kind() != RepoKindVcs {
+		return ""
+	}
+	if c.Type != "vcs" {
+		return c.Type
+	}
+
+	url := strings.ToLower(c.NormalizedUrl())
+	switch {
+	case strings.HasPrefix(url, "https://github.com/"):
+		return "github"
+	case strings.HasPrefix(url, "https://gitlab.com/"):
+		return "gitlab"
+	case strings.HasPrefix(url, "https://bitbucket.org/"):
+		return "bitbucket"
+	case strings.HasPrefix(url, "svn://") || strings.HasPrefix(url, "svn+ssh://") ||
+		strings.Contains(url, "/svn/") || strings.HasSuffix(url, "/trunk"):
+		return "svn"
+	case strings.HasPrefix(url, "hg://") || strings.Contains(url, "/hg/"):
+		return "hg"
+	}
+	return "git"
+}
+
+// scpUrlRegexp matches the scp-like urls of VCS repositories,
+// for example, git@github.com:owner/repo.git.
+var scpUrlRegexp = regexp.MustCompile(`^(?:[^@/]+@)?([^:/]+):(.+)$`)
+
+// NormalizedUrl returns the url of a VCS repository in a form that
+// is the same for the ssh and https forms of the same repository,
+// for comparing repositories: the ssh, scp-like and git urls are
+// converted to https, the host is lowercased, and the trailing
+// slash and .git are removed.
+//
+// Example:
+//
+//	git@github.com:owner/repo.git   -> https://github.com/owner/repo
+//	ssh://git@GitHub.com/owner/repo -> https://github.com/owner/repo
+//
+// The urls of local repositories and of other kinds are returned as is.
+func (c *ConfigRepo) NormalizedUrl() string {
+	if c.Kind() != RepoKindVcs || isLocalRepoUrl(c.Url) {
+		return c.Url
+	}
+
+	url := c.Url
+	if !strings.Contains(url, "://") {
+		m := scpUrlRegexp.FindStringSubmatch(url)
+		if m == nil {
+			return c.Url
+		}
+		url = "https://" + m[1] + "/" + strings.TrimPrefix(m[2], "/")
+	}
+
+	scheme := url[:strings.Index(url, "://")]
+	rest := url[len(scheme)+3:]
+
+	switch scheme {
+	case "ssh", "git", "git+ssh", "http", "https":
+		scheme = "https"
+	default:
+		return strings.TrimSuffix(url, "/")
+	}
+
+	host, path := rest, ""
+	if i := strings.IndexByte(rest, '/'); i >= 0 {
+		host, path = rest[:i], rest[i:]
+	}
+	if i := strings.LastIndexByte(host, '@'); i >= 0 {
+		host = host[i+1:]
+	}
+	if i := strings.IndexByte(host, ':'); i >= 0 {
+		host = host[:i]
+	}
+
+	path = strings.TrimSuffix(strings.TrimSuffix(path, "/"), ".git")
+	return scheme + "://" + strings.ToLower(host) + path
+}
+
+// isLocalRepoUrl reports whether the url of a repository
+// is a path on the local filesystem.
+func isLocalRepoUrl(url string) bool {
+	if strings.HasPrefix(url, "file://") {
+		return true
+	}
+	if strings.Contains(url, "://") || scpUrlRegexp.MatchString(url) && !filepath.IsAbs(url) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,122 @@
+package composer
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestComposerRepoOptions(t *testing.T) {
+	config, errs := NewConfigFromData([]byte(`{
+		"version": "1.0.0",
+		"repositories": [
+			{
+				"type": "composer",
+				"name": "internal",
+				"url": "https://packages.example.com",
+				"allow_ssl_downgrade": true,
+				"options": {
+					"ssl": {"verify_peer": false, "cafile": "/etc/ssl/ca.pem"},
+					"http": {"header": ["API-TOKEN: secret"], "timeout": 30}
+				}
+			},
+			{"type": "path", "url": "../lib"}
+		]
+	}`), "composer.json")
+	if errs != nil {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	repo := config.Reps[0]
+	if !repo.IsComposer() || repo.Name != "internal" || !repo.AllowSslDowngrade {
+		t.Errorf("unexpected repository: %+v", repo)
+	}
+	if repo.Options == nil || repo.Options.SSL.VerifyPeer == nil || *repo.Options.SSL.VerifyPeer || repo.Options.SSL.Cafile != "/etc/ssl/ca.pem" {
+		t.Errorf("unexpected ssl options: %+v", repo.Options)
+	}
+	if !reflect.DeepEqual(repo.Options.HTTP.Header, RepoHeaders{"API-TOKEN: secret"}) || repo.Options.HTTP.Timeout != 30 {
+		t.Errorf("unexpected http options: %+v", repo.Options.HTTP)
+	}
+
+	var options RepoOptions
+	if err := json.Unmarshal([]byte(`{"http": {"header": "API-TOKEN: secret\r\nX-Team: billing"}}`), &options); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(options.HTTP.Header, RepoHeaders{"API-TOKEN: secret", "X-Team: billing"}) {
+		t.Errorf("unexpected headers from a string: %v", options.HTTP.Header)
+	}
+	if err := json.Unmarshal([]byte(`{"http": {"header": 42}}`), &options); err == nil {
+		t.Errorf("expected an error for a header of the wrong type")
+	}
+
+	if config.Reps[1].IsComposer() || config.Reps[1].Options != nil {
+		t.Errorf("unexpected path repository: %+v", config.Reps[1])
+	}
+}
+
+func TestVcsRepos(t *testing.T) {
+	tests := []struct {
+		repo       ConfigRepo
+		kind       RepoKind
+		vcsType    string
+		normalized string
+	}{
+		{
+			repo:       ConfigRepo{Type: "vcs", Url: "git@github.com:Owner/Repo.git"},
+			kind:       RepoKindVcs,
+			vcsType:    "github",
+			normalized: "https://github.com/Owner/Repo",
+		},
+		{
+			repo:       ConfigRepo{Type: "git", Url: "ssh://git@GitLab.example.com:2222/group/repo.git/"},
+			kind:       RepoKindVcs,
+			vcsType:    "git",
+			normalized: "https://gitlab.example.com/group/repo",
+		},
+		{
+			repo:       ConfigRepo{Type: "vcs", Url: "https://bitbucket.org/owner/repo"},
+			kind:       RepoKindVcs,
+			vcsType:    "bitbucket",
+			normalized: "https://bitbucket.org/owner/repo",
+		},
+		{
+			repo:       ConfigRepo{Type: "vcs", Url: "svn://svn.example.com/project/trunk"},
+			kind:       RepoKindVcs,
+			vcsType:    "svn",
+			normalized: "svn://svn.example.com/project/trunk",
+		},
+		{
+			repo:       ConfigRepo{Type: "composer", Url: "https://packages.example.com/"},
+			kind:       RepoKindComposer,
+			normalized: "https://packages.example.com/",
+		},
+		{
+			repo:       ConfigRepo{Type: "git", Url: "../libs/repo.git"},
+			kind:       RepoKindVcs,
+			vcsType:    "git",
+			normalized: "../libs/repo.git",
+		},
+	}
+
+	for _, tt := range tests {
+		if kind := tt.repo.Kind(); kind != tt.kind {
+			t.Errorf("%s: Kind() = %q, want %q", tt.repo.Url, kind, tt.kind)
+		}
+		if vcsType := tt.repo.VcsType(); vcsType != tt.vcsType {
+			t.Errorf("%s: VcsType() = %q, want %q", tt.repo.Url, vcsType, tt.vcsType)
+		}
+		if normalized := tt.repo.NormalizedUrl(); normalized != tt.normalized {
+			t.Errorf("%s: NormalizedUrl() = %q, want %q", tt.repo.Url, normalized, tt.normalized)
+		}
+	}
+
+	local := &ConfigRepo{Type: "git", Url: "../libs/repo.git"}
+	if resolved := local.ResolveUrl("/project"); resolved.Url != "/libs/repo.git" || !resolved.Resolved {
+		t.Errorf("expected local git repository to be resolved, got %+v", resolved)
+	}
+
+	remote := &ConfigRepo{Type: "github", Url: "git@github.com:owner/repo.git"}
+	if resolved := remote.ResolveUrl("/project"); resolved.Url != "git@github.com:owner/repo.git" || resolved.Resolved {
+		t.Errorf("expected remote repository to be left as is, got %+v", resolved)
+	}
+}
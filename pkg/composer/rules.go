@@ -0,0 +1,166 @@
+package composer
+
+import (
+	"sort"
+)
+
+// Codes of the built-in rules.
+const (
+	CodeInvalidVersion          = "invalid-version"
+	CodeInvalidTime             = "invalid-time"
+	CodeInvalidMinimumStability = "invalid-minimum-stability"
+	CodeInvalidSuppression      = "invalid-suppression"
+)
+
+// Rule describes one of the built-in checks.
+type Rule struct {
+	// Code is the value of ConfigError.Code for errors
+	// reported by the rule.
+	Code string
+	// Description is a short human-readable description.
+	Description string
+	// Critical is the default severity of the errors.
+	Critical bool
+	// Fixable reports whether the errors can be fixed automatically.
+	Fixable bool
+}
+
+// builtinRules is a catalog of all built-in checks.
+//
+// Every built-in check must be added here,
+// otherwise it will not be listed by Rules.
+var builtinRules = []Rule{
+	{
+		Code:        CodeInvalidVersion,
+		Description: "The version field is missing or is not in the format [v]X.Y.Z[-suffix].",
+	},
+	{
+		Code:        CodeInvalidTime,
+		Description: "The time field is not in the format YYYY-MM-DD or YYYY-MM-DD HH:MM:SS.",
+	},
+	{
+		Code:        CodeInvalidMinimumStability,
+		Description: "The minimum-stability field is not one of dev, alpha, beta, RC or stable.",
+	},
+	{
+		Code:        CodeInvalidSuppression,
+		Description: `The extra."composer-check".ignore list is malformed.`,
+	},
+	{
+		Code:        CodeInvalidLicense,
+		Description: `The license is not "proprietary", a known SPDX license identifier or a valid SPDX expression.`,
+	},
+	{
+		Code:        CodeInvalidKeyword,
+		Description: "A keyword is empty, duplicated or longer than MaxKeywordLength, see CheckKeywords.",
+	},
+	{
+		Code:        CodeInvalidSuggest,
+		Description: "A suggest reason is empty or is a version constraint instead of a human-readable text, see CheckSuggest.",
+	},
+	{
+		Code:        CodeInvalidBranchAlias,
+		Description: "An extra.branch-alias entry is ignored by composer: the source is not a dev branch or the alias is not a numeric -dev version, see CheckBranchAliases.",
+	},
+	{
+		Code:        CodePlatformOverride,
+		Description: "A config.platform override is invalid, does not satisfy the requirement of the package or disables a required package, see CheckPlatformOverrides.",
+	},
+	{
+		Code:        CodeUnsatisfiedPlatform,
+		Description: "A php or ext-* requirement is not available or not satisfied on the platform, see Platform.Check.",
+	},
+	{
+		Code:        CodeInvalidNonFeatureBranch,
+		Description: "A non-feature-branches pattern is empty or is not a valid regular expression.",
+	},
+	{
+		Code:        CodeInvalidType,
+		Description: "The type is neither a known package type nor a custom installer type in the form vendor-type.",
+	},
+	{
+		Code:        CodeAmbiguousNamespace,
+		Description: "Several configs can satisfy a namespace passed to ResolveNamespace, the error is critical with the NamespaceError strategy.",
+	},
+	{
+		Code:        CodeInvalidAutoload,
+		Description: "An autoload mapping is malformed: the path is empty or the psr-4 prefix does not end with a namespace separator, see AutoloadPathsCheck.",
+	},
+	{
+		Code:        CodeMissingAutoloadPath,
+		Description: "An autoload path does not exist and is not marked as generated, see AutoloadPathsCheck.",
+	},
+	{
+		Code:        CodeInvalidTargetDir,
+		Description: "The legacy target-dir is used together with autoload.psr-4.",
+	},
+	{
+		Code:        CodeSuspiciousKey,
+		Description: "A key is misspelled, is at the wrong level, or an autoload list is given as a string; such keys are silently ignored.",
+	},
+	{
+		Code:        CodeUnsupportedKey,
+		Description: "A key is not supported by the composer version set with LoadOptions.SchemaVersion and is ignored by it.",
+	},
+	{
+		Code:        CodeInvalidFieldType,
+		Description: "A field has a value of the wrong JSON type, for example, an array instead of a string.",
+		Critical:    true,
+	},
+	{
+		Code:        CodeReservedNamespace,
+		Description: "A psr-4 prefix claims a namespace reserved for another package or team, see NamespaceRegistry.",
+	},
+	{
+		Code:        CodeRedundantPolyfill,
+		Description: "A required polyfill provides functionality native in the minimum required PHP version, see CheckPolyfills.",
+	},
+	{
+		Code:        CodePluginNotAllowed,
+		Description: "A locked plugin is not allowed by config.allow-plugins and is not run by composer 2.2+, see AllowPluginsCheck.",
+	},
+	{
+		Code:        CodeVendorNotAllowed,
+		Description: "A package in the dependency tree belongs to a vendor not allowed by the policy, see VendorPolicy.",
+	},
+	{
+		Code:        CodeVirtualRootConflict,
+		Description: "Configs composed into a virtual root require different constraints for a package or map the same namespace to different paths.",
+		Critical:    true,
+	},
+	{
+		Code:        CodeUnpinnedRequirement,
+		Description: "A requirement refers to a branch or dev versions without a pinned commit reference, see CheckPinnedRequirements.",
+	},
+	{
+		Code:        CodeMutableDist,
+		Description: "The dist URL of a locked package points at a mutable branch or archive, see CheckPinnedDists.",
+	},
+	{
+		Code:        CodeExternalCheckFailed,
+		Description: "An external check provider cannot be run or returned an invalid response.",
+		Critical:    true,
+	},
+}
+
+// Rules returns all built-in checks sorted by code.
+func Rules() []Rule {
+	res := make([]Rule, len(builtinRules))
+	copy(res, builtinRules)
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Code < res[j].Code
+	})
+
+	return res
+}
+
+// RuleByCode returns the built-in check with the passed code.
+func RuleByCode(code string) (Rule, bool) {
+	for _, rule := range builtinRules {
+		if rule.Code == code {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
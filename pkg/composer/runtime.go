@@ -0,0 +1,223 @@
+package composer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/i582/go-composer.json/internal/version"
+)
+
+// CodeUnsatisfiedPlatform is the code of the errors reported
+// by Platform.Check.
+const CodeUnsatisfiedPlatform = "unsatisfied-platform"
+
+// Platform is the PHP runtime the packages are installed for:
+// the version of PHP and the loaded extensions.
+//
+// A platform is a check provider reporting the platform requirements
+// of the config that it does not satisfy.
+//
+// Example:
+//
+//	platform, err := composer.DetectPlatform("")
+//	if err != nil {
+//	  platform = composer.StaticPlatform(cfg)
+//	}
+//	cfg.AddCheckProvider(platform.WithOverrides(cfg.Settings.Platform))
+type Platform struct {
+	// PhpVersion is the version of PHP, for example, 8.1.2.
+	PhpVersion string `json:"php"`
+	// Extensions maps the lowercase names of the loaded
+	// extensions to their versions, the version is empty
+	// if the extension does not report it.
+	Extensions map[string]string `json:"extensions"`
+	// Partial is true if the platform describes only some of the
+	// packages, as StaticPlatform does. The requirements on the
+	// packages it does not describe are not checked, rather than
+	// reported as not available, unless the packages are disabled
+	// with config.platform.
+	Partial bool `json:"-"`
+
+	// disabled are the lowercase names of the extensions
+	// disabled with config.platform, see WithOverrides.
+	disabled map[string]bool
+}
+
+// detectSnippet prints the version of PHP and the loaded extensions as JSON.
+const detectSnippet = `$e = array();
+foreach (get_loaded_extensions() as $name) {
+    $e[strtolower($name)] = (string) phpversion($name);
+}
+echo json_encode(array('php' => PHP_VERSION, 'extensions' => (object) $e));`
+
+// DefaultDetectPlatformTimeout is the time the php binary
+// is given to finish in DetectPlatform.
+const DefaultDetectPlatformTimeout = 10 * time.Second
+
+// DetectPlatform runs the php binary to collect the version of PHP
+// and the loaded extensions. If the path is empty, php is looked up
+// in PATH. The binary is killed if it does not finish within
+// DefaultDetectPlatformTimeout.
+//
+// If PHP is not available, use StaticPlatform.
+func DetectPlatform(php string) (*Platform, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultDetectPlatformTimeout)
+	defer cancel()
+	return DetectPlatformContext(ctx, php)
+}
+
+// DetectPlatformContext is like DetectPlatform, but the php binary
+// is killed when the context is done instead of after the default timeout.
+func DetectPlatformContext(ctx context.Context, php string) (*Platform, error) {
+	if php == "" {
+		var err error
+		php, err = exec.LookPath("php")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, php, "-r", detectSnippet)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("%s: %v", php, ctx.Err())
+		}
+		return nil, fmt.Errorf("%s: %v: %s", php, err, strings.TrimSpace(stderr.String()))
+	}
+
+	var platform Platform
+	if err := json.Unmarshal(stdout.Bytes(), &platform); err != nil {
+		return nil, fmt.Errorf("%s: invalid output: %v", php, err)
+	}
+	if platform.Extensions == nil {
+		platform.Extensions = map[string]string{}
+	}
+
+	return &platform, nil
+}
+
+// StaticPlatform returns the platform described by the config.platform
+// setting of the config, without running PHP.
+//
+// The platform is partial: the packages not listed in config.platform,
+// usually most extensions and often php itself, are unknown and
+// their requirements are not checked, see Platform.Partial.
+func StaticPlatform(c *Config) *Platform {
+	return (&Platform{Extensions: map[string]string{}, Partial: true}).WithOverrides(c.Settings.Platform)
+}
+
+// WithOverrides returns a copy of the platform with the overrides
+// of config.platform applied, as composer does: overridden packages
+// get the version of the override, and packages set to false are removed.
+func (p *Platform) WithOverrides(overrides PlatformOverrides) *Platform {
+	res := &Platform{
+		PhpVersion: p.PhpVersion,
+		Extensions: make(map[string]string, len(p.Extensions)),
+		Partial:    p.Partial,
+		disabled:   map[string]bool{},
+	}
+	for name, ver := range p.Extensions {
+		res.Extensions[name] = ver
+	}
+	for name := range p.disabled {
+		res.disabled[name] = true
+	}
+
+	for name, ver := range overrides {
+		switch {
+		case name == "php":
+			res.PhpVersion = ver
+		case strings.HasPrefix(name, "ext-"):
+			ext := strings.ToLower(strings.TrimPrefix(name, "ext-"))
+			if ver == "" {
+				delete(res.Extensions, ext)
+				res.disabled[ext] = true
+			} else {
+				res.Extensions[ext] = ver
+				delete(res.disabled, ext)
+			}
+		}
+	}
+
+	return res
+}
+
+// Package returns the version of the platform package (php or ext-*)
+// and whether the package is available.
+func (p *Platform) Package(name string) (string, bool) {
+	if name == "php" {
+		return p.PhpVersion, p.PhpVersion != ""
+	}
+
+	if strings.HasPrefix(name, "ext-") {
+		ver, ok := p.Extensions[strings.ToLower(strings.TrimPrefix(name, "ext-"))]
+		return ver, ok
+	}
+
+	return "", false
+}
+
+// Check implements the CheckProvider interface.
+//
+// The php and ext-* requirements of the config are checked, extensions
+// with a version that cannot be parsed are only checked for presence.
+// Constraints that cannot be parsed are reported as unsatisfied.
+// The packages unknown to a partial platform are skipped.
+func (p *Platform) Check(c *Config) []*ConfigError {
+	var errors []*ConfigError
+	for _, name := range sortedKeys(c.Require) {
+		if name != "php" && !strings.HasPrefix(name, "ext-") {
+			continue
+		}
+
+		msg := p.checkRequirement(name, c.Require[name])
+		if msg == "" {
+			continue
+		}
+
+		errors = append(errors, &ConfigError{
+			Msg:      msg,
+			Critical: false,
+			Code:     CodeUnsatisfiedPlatform,
+			Pointer:  "/require/" + escapePointer(name),
+		})
+	}
+
+	return errors
+}
+
+// leadingVersionRegexp matches the numeric part of the versions
+// reported by PHP, for example, 8.1.2 in 8.1.2-1ubuntu2.
+var leadingVersionRegexp = regexp.MustCompile(`^\d+(\.\d+)*`)
+
+func (p *Platform) checkRequirement(name, required string) string {
+	ver, ok := p.Package(name)
+	if !ok && p.Partial && (name == "php" || !p.disabled[strings.ToLower(strings.TrimPrefix(name, "ext-"))]) {
+		return ""
+	}
+	if !ok {
+		return fmt.Sprintf("required platform package '%s' is not available", name)
+	}
+
+	constraint, err := version.NewConstraint(required)
+	if err != nil {
+		return fmt.Sprintf("constraint '%s' of platform package '%s' cannot be checked: %v", required, name, err)
+	}
+
+	parsed, err := parsePlatformVersion(leadingVersionRegexp.FindString(ver))
+	if err != nil || constraint.Allows(parsed) {
+		return ""
+	}
+
+	return fmt.Sprintf("platform package '%s' %s does not satisfy the required constraint '%s'", name, ver, required)
+}
@@ -0,0 +1,95 @@
+package composer
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDetectPlatform(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+
+	dir, err := ioutil.TempDir("", "composer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	php := filepath.Join(dir, "php")
+	script := "#!/bin/sh\necho '{\"php\": \"8.1.2-1ubuntu2\", \"extensions\": {\"json\": \"8.1.2\", \"intl\": \"\"}}'\n"
+	if err := ioutil.WriteFile(php, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	platform, err := DetectPlatform(php)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if platform.PhpVersion != "8.1.2-1ubuntu2" || len(platform.Extensions) != 2 {
+		t.Errorf("unexpected platform: %+v", platform)
+	}
+
+	config, _ := NewConfigFromData([]byte(`{
+		"version": "1.0.0",
+		"require": {"php": "^8.2", "ext-json": "*", "ext-intl": "*", "ext-mbstring": "*", "foo/bar": "^1.0"},
+		"config": {"platform": {"ext-mbstring": "8.1.0"}}
+	}`), "composer.json")
+
+	var pointers []string
+	for _, err := range platform.Check(config) {
+		pointers = append(pointers, err.Pointer)
+	}
+	expected := []string{"/require/ext-mbstring", "/require/php"}
+	if !reflect.DeepEqual(pointers, expected) {
+		t.Errorf("mismatch pointers:\nwant: %v\nhave: %v", expected, pointers)
+	}
+
+	if errs := platform.WithOverrides(config.Settings.Platform).Check(config); len(errs) != 1 {
+		t.Errorf("expected only php to be unsatisfied with overrides, got %v", errs)
+	}
+
+	static := StaticPlatform(config)
+	if ver, ok := static.Package("ext-mbstring"); !ok || ver != "8.1.0" {
+		t.Errorf("unexpected static platform: %+v", static)
+	}
+	if errs := static.Check(config); len(errs) != 0 {
+		t.Errorf("expected the packages unknown to the static platform to be skipped, got %v", errs)
+	}
+
+	config.Settings.Platform = PlatformOverrides{"php": "8.1.0", "ext-intl": ""}
+	pointers = nil
+	for _, err := range StaticPlatform(config).Check(config) {
+		pointers = append(pointers, err.Pointer)
+	}
+	expected = []string{"/require/ext-intl", "/require/php"}
+	if !reflect.DeepEqual(pointers, expected) {
+		t.Errorf("expected the disabled extension and the overridden php to be checked, got %v", pointers)
+	}
+
+	config, _ = NewConfigFromData([]byte(`{
+		"version": "1.0.0",
+		"require": {"php": "^8.1 ||| ^9.0", "ext-json": ">= 8.0"}
+	}`), "composer.json")
+	errs := platform.Check(config)
+	if len(errs) != 1 || errs[0].Pointer != "/require/php" || !strings.Contains(errs[0].Msg, "cannot be checked") {
+		t.Errorf("expected the unparsable constraint to be reported, got %v", errs)
+	}
+
+	slow := filepath.Join(dir, "slow-php")
+	if err := ioutil.WriteFile(slow, []byte("#!/bin/sh\nexec sleep 10\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := DetectPlatformContext(ctx, slow); err == nil {
+		t.Errorf("expected the detection to time out")
+	}
+}
@@ -0,0 +1,113 @@
+package composer
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// scriptEvents are the events composer fires itself,
+// all other names in the scripts section are custom commands.
+var scriptEvents = map[string]bool{
+	"pre-install-cmd":           true,
+	"post-install-cmd":          true,
+	"pre-update-cmd":            true,
+	"post-update-cmd":           true,
+	"pre-status-cmd":            true,
+	"post-status-cmd":           true,
+	"pre-archive-cmd":           true,
+	"post-archive-cmd":          true,
+	"pre-autoload-dump":         true,
+	"post-autoload-dump":        true,
+	"post-root-package-install": true,
+	"post-create-project-cmd":   true,
+	"pre-operations-exec":       true,
+	"pre-package-install":       true,
+	"post-package-install":      true,
+	"pre-package-update":        true,
+	"post-package-update":       true,
+	"pre-package-uninstall":     true,
+	"post-package-uninstall":    true,
+	"init":                      true,
+	"command":                   true,
+	"pre-file-download":         true,
+	"post-file-download":        true,
+	"pre-command-run":           true,
+	"pre-pool-create":           true,
+}
+
+// IsScriptEvent returns true if the name is an event fired by
+// composer itself rather than a custom command.
+func IsScriptEvent(name string) bool {
+	return scriptEvents[name]
+}
+
+// Scripts is the scripts section of composer.json.
+//
+// In composer.json each event maps either to a single command
+// or to an array of commands, both forms are stored as a list.
+//
+// Examples:
+//
+//	"post-update-cmd": "MyVendor\\MyClass::postUpdate"
+//	"post-install-cmd": ["MyVendor\\MyClass::warmCache", "phpunit -c app/"]
+type Scripts map[string][]string
+
+// UnmarshalJSON implements the json.Unmarshaler interface
+// for both the string and the array forms of commands.
+func (s *Scripts) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("scripts must be an object")
+	}
+
+	scripts := make(Scripts, len(raw))
+	for event, value := range raw {
+		var single string
+		if err := json.Unmarshal(value, &single); err == nil {
+			scripts[event] = []string{single}
+			continue
+		}
+
+		var list []string
+		if err := json.Unmarshal(value, &list); err != nil {
+			return fmt.Errorf("script %q must be a string or an array of strings", event)
+		}
+		scripts[event] = list
+	}
+
+	*s = scripts
+	return nil
+}
+
+// Script returns the commands and the description of the script
+// with the passed name. The name may be an alias of a custom
+// command defined in scripts-aliases.
+func (c *Config) Script(name string) (commands []string, description string, ok bool) {
+	if commands, ok := c.Scripts[name]; ok {
+		return commands, c.ScriptsDescriptions[name], true
+	}
+
+	for script, aliases := range c.ScriptsAliases {
+		for _, alias := range aliases {
+			if alias == name {
+				return c.Scripts[script], c.ScriptsDescriptions[script], c.Scripts[script] != nil
+			}
+		}
+	}
+
+	return nil, "", false
+}
+
+// CustomScripts returns the sorted names of the scripts that are
+// not composer events and can be run by "composer run-script".
+func (c *Config) CustomScripts() []string {
+	var names []string
+	for name := range c.Scripts {
+		if !IsScriptEvent(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,58 @@
+package composer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestScripts(t *testing.T) {
+	config, errs := NewConfigFromData([]byte(`{
+		"version": "1.0.0",
+		"scripts": {
+			"post-update-cmd": "MyVendor\\MyClass::postUpdate",
+			"test": ["@clearCache", "phpunit"]
+		}
+	}`), "composer.json")
+	if errs != nil {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	expected := Scripts{
+		"post-update-cmd": {`MyVendor\MyClass::postUpdate`},
+		"test":            {"@clearCache", "phpunit"},
+	}
+	if !reflect.DeepEqual(config.Scripts, expected) {
+		t.Errorf("mismatch scripts:\nwant: %v\nhave: %v", expected, config.Scripts)
+	}
+}
+
+func TestScript(t *testing.T) {
+	config, _ := NewConfigFromData([]byte(`{
+		"version": "1.0.0",
+		"scripts": {
+			"post-install-cmd": "@test",
+			"test": ["phpunit"],
+			"phpstan": "phpstan analyse"
+		},
+		"scripts-descriptions": {"test": "Run all tests!"},
+		"scripts-aliases": {"phpstan": ["stan", "analyze"]}
+	}`), "composer.json")
+
+	commands, description, ok := config.Script("test")
+	if !ok || description != "Run all tests!" || !reflect.DeepEqual(commands, []string{"phpunit"}) {
+		t.Errorf("unexpected test script: %v, %q, %v", commands, description, ok)
+	}
+
+	commands, _, ok = config.Script("stan")
+	if !ok || !reflect.DeepEqual(commands, []string{"phpstan analyse"}) {
+		t.Errorf("unexpected alias script: %v, %v", commands, ok)
+	}
+
+	if _, _, ok := config.Script("unknown"); ok {
+		t.Errorf("expected unknown script not to be found")
+	}
+
+	if custom := config.CustomScripts(); !reflect.DeepEqual(custom, []string{"phpstan", "test"}) {
+		t.Errorf("unexpected custom scripts: %v", custom)
+	}
+}
@@ -0,0 +1,211 @@
+package composer
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ComposerConfig is a structure for storing
+// the config section of composer.json.
+//
+// Fields that are not set in composer.json have
+// the default values, see DefaultComposerConfig.
+type ComposerConfig struct {
+	// The timeout in seconds for process executions, defaults to 300 (5mins).
+	ProcessTimeout int `json:"process-timeout"`
+	// Plugins allowed to run, either true/false for all plugins,
+	// or a map of package name patterns to a flag.
+	AllowPlugins AllowPlugins `json:"allow-plugins"`
+	// Whether to use the Composer PHP include path, defaults to false.
+	UseIncludePath bool `json:"use-include-path"`
+	// The install method Composer will prefer to use, defaults to dist.
+	PreferredInstall PreferredInstall `json:"preferred-install"`
+	// Whether to store authentication when prompted: true, false or "prompt".
+	StoreAuths BoolOrString `json:"store-auths"`
+	// A list of protocols to use when cloning from github.com,
+	// defaults to https, ssh, git.
+	GithubProtocols []string `json:"github-protocols"`
+	// A list of domains to use in github mode, defaults to github.com.
+	GithubDomains []string `json:"github-domains"`
+	// Whether to use the GitHub API for github repositories, defaults to true.
+	UseGithubApi bool `json:"use-github-api"`
+	// Whether to append the machine hostname to the GitHub token, defaults to true.
+	GithubExposeHostname bool `json:"github-expose-hostname"`
+	// A list of domains of GitLab servers, defaults to gitlab.com.
+	GitlabDomains []string `json:"gitlab-domains"`
+	// Whether to disable TLS for all connections, defaults to false.
+	DisableTls bool `json:"disable-tls"`
+	// Whether only HTTPS URLs are allowed to be downloaded, defaults to true.
+	SecureHttp bool `json:"secure-http"`
+	// Location of Certificate Authority file on local filesystem.
+	Cafile string `json:"cafile"`
+	// Path to a directory that contains hashed certificate files.
+	Capath string `json:"capath"`
+	// Platform packages overrides, see PlatformOverrides.
+	Platform PlatformOverrides `json:"platform"`
+	// By default the vendor dir is vendor,
+	// it can be overridden by the COMPOSER_VENDOR_DIR env var.
+	VendorDir string `json:"vendor-dir"`
+	// By default the bin dir is {$vendor-dir}/bin,
+	// it can be overridden by the COMPOSER_BIN_DIR env var.
+	BinDir string `json:"bin-dir"`
+	// The directory where Composer stores its own data,
+	// by default {$home} on unix.
+	DataDir string `json:"data-dir"`
+	// By default the cache dir is {$home}/cache if COMPOSER_HOME is set,
+	// otherwise the user cache dir, it can be overridden by the
+	// COMPOSER_CACHE_DIR env var.
+	CacheDir string `json:"cache-dir"`
+	// Stores the zip archives of packages, defaults to {$cache-dir}/files.
+	CacheFilesDir string `json:"cache-files-dir"`
+	// Stores repository metadata, defaults to {$cache-dir}/repo.
+	CacheRepoDir string `json:"cache-repo-dir"`
+	// Stores the cloned VCS repositories, defaults to {$cache-dir}/vcs.
+	CacheVcsDir string `json:"cache-vcs-dir"`
+	// The cache time-to-live of files in seconds, defaults to 15552000 (6 months).
+	CacheFilesTtl int `json:"cache-files-ttl"`
+	// The maximum size of the files cache, defaults to 300MiB.
+	CacheFilesMaxsize string `json:"cache-files-maxsize"`
+	// Whether the cache is read-only, defaults to false.
+	CacheReadOnly bool `json:"cache-read-only"`
+	// The compatibility mode of binaries: auto, full, proxy or symlink.
+	BinCompat string `json:"bin-compat"`
+	// Whether to prepend the autoloader to the existing autoloaders, defaults to true.
+	PrependAutoloader bool `json:"prepend-autoloader"`
+	// The suffix of the generated autoloader class, defaults to null.
+	AutoloaderSuffix string `json:"autoloader-suffix"`
+	// Always optimize when dumping the autoloader, defaults to false.
+	OptimizeAutoloader bool `json:"optimize-autoloader"`
+	// Sort packages by name when adding a new package, defaults to false.
+	SortPackages bool `json:"sort-packages"`
+	// Do not scan the PSR-0/4 directories for classes, defaults to false.
+	ClassmapAuthoritative bool `json:"classmap-authoritative"`
+	// Use APCu to cache found/not-found classes, defaults to false.
+	ApcuAutoloader bool `json:"apcu-autoloader"`
+	// Whether to send install notifications to the repository, defaults to true.
+	NotifyOnInstall bool `json:"notify-on-install"`
+	// How to handle dirty updates of vendors: true, false or "stash".
+	DiscardChanges BoolOrString `json:"discard-changes"`
+	// The default format of archives, defaults to tar.
+	ArchiveFormat string `json:"archive-format"`
+	// The default destination of archives, defaults to the current dir.
+	ArchiveDir string `json:"archive-dir"`
+	// Whether to create .htaccess files in the Composer home,
+	// cache and data dirs, defaults to true.
+	HtaccessProtect bool `json:"htaccess-protect"`
+	// Whether the composer.lock file is used, defaults to true.
+	Lock bool `json:"lock"`
+	// Whether the platform check is generated: true, false or "php-only".
+	PlatformCheck BoolOrString `json:"platform-check"`
+}
+
+// DefaultComposerConfig returns the config section with
+// the default values, as in composer.
+func DefaultComposerConfig() ComposerConfig {
+	return ComposerConfig{
+		ProcessTimeout:       300,
+		PreferredInstall:     PreferredInstall{Default: "dist"},
+		StoreAuths:           BoolOrString{String: "prompt"},
+		GithubProtocols:      []string{"https", "ssh", "git"},
+		GithubDomains:        []string{"github.com"},
+		UseGithubApi:         true,
+		GithubExposeHostname: true,
+		GitlabDomains:        []string{"gitlab.com"},
+		SecureHttp:           true,
+		VendorDir:            "vendor",
+		BinDir:               "{$vendor-dir}/bin",
+		CacheFilesTtl:        15552000,
+		CacheFilesMaxsize:    "300MiB",
+		BinCompat:            "auto",
+		PrependAutoloader:    true,
+		NotifyOnInstall:      true,
+		DiscardChanges:       BoolOrString{Bool: false},
+		ArchiveFormat:        "tar",
+		ArchiveDir:           ".",
+		HtaccessProtect:      true,
+		Lock:                 true,
+		PlatformCheck:        BoolOrString{String: "php-only"},
+	}
+}
+
+// BoolOrString is a value of settings that can be
+// either a boolean or a string, for example, discard-changes.
+//
+// If the value is a string, String is not empty.
+type BoolOrString struct {
+	Bool   bool
+	String string
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (v *BoolOrString) UnmarshalJSON(data []byte) error {
+	var b bool
+	if err := json.Unmarshal(data, &b); err == nil {
+		*v = BoolOrString{Bool: b}
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("value must be a boolean or a string")
+	}
+
+	*v = BoolOrString{String: s}
+	return nil
+}
+
+// PreferredInstall is the value of the preferred-install setting,
+// either a single method for all packages or a map of package
+// name patterns to methods.
+type PreferredInstall struct {
+	// Default is the method for all packages when
+	// the setting is a string.
+	Default string
+	// Patterns maps package name patterns to methods
+	// when the setting is an object.
+	Patterns map[string]string
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (p *PreferredInstall) UnmarshalJSON(data []byte) error {
+	var method string
+	if err := json.Unmarshal(data, &method); err == nil {
+		*p = PreferredInstall{Default: method}
+		return nil
+	}
+
+	var patterns map[string]string
+	if err := json.Unmarshal(data, &patterns); err != nil {
+		return fmt.Errorf("preferred-install must be a string or an object")
+	}
+
+	*p = PreferredInstall{Patterns: patterns}
+	return nil
+}
+
+// AllowPlugins is the value of the allow-plugins setting.
+type AllowPlugins struct {
+	// All is set when the setting is a boolean and
+	// applies to all plugins.
+	All *bool
+	// Plugins maps package name patterns to a flag
+	// when the setting is an object.
+	Plugins map[string]bool
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (a *AllowPlugins) UnmarshalJSON(data []byte) error {
+	var all bool
+	if err := json.Unmarshal(data, &all); err == nil {
+		*a = AllowPlugins{All: &all}
+		return nil
+	}
+
+	var plugins map[string]bool
+	if err := json.Unmarshal(data, &plugins); err != nil {
+		return fmt.Errorf("allow-plugins must be a boolean or an object")
+	}
+
+	*a = AllowPlugins{Plugins: plugins}
+	return nil
+}
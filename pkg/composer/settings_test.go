@@ -0,0 +1,46 @@
+package composer
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestComposerConfig(t *testing.T) {
+	config, _ := NewConfigFromData([]byte(`{
+		"config": {
+			"vendor-dir": "lib/vendor",
+			"process-timeout": 600,
+			"sort-packages": true,
+			"secure-http": false,
+			"discard-changes": "stash",
+			"preferred-install": {"my/*": "source", "*": "dist"},
+			"allow-plugins": {"composer/installers": true},
+			"lock": false
+		}
+	}`), "composer.json")
+
+	settings := config.Settings
+	if settings.VendorDir != "lib/vendor" || settings.ProcessTimeout != 600 || !settings.SortPackages {
+		t.Errorf("unexpected settings: %+v", settings)
+	}
+	if settings.SecureHttp || settings.Lock {
+		t.Errorf("expected secure-http and lock to be disabled")
+	}
+	if settings.DiscardChanges.String != "stash" {
+		t.Errorf("unexpected discard-changes: %+v", settings.DiscardChanges)
+	}
+	if settings.PreferredInstall.Patterns["my/*"] != "source" {
+		t.Errorf("unexpected preferred-install: %+v", settings.PreferredInstall)
+	}
+	if settings.AllowPlugins.All != nil || !settings.AllowPlugins.Plugins["composer/installers"] {
+		t.Errorf("unexpected allow-plugins: %+v", settings.AllowPlugins)
+	}
+
+	// Not set values must have defaults.
+	if settings.BinDir != "{$vendor-dir}/bin" || !settings.UseGithubApi || settings.CacheFilesTtl != 15552000 {
+		t.Errorf("expected default values, got %+v", settings)
+	}
+	if dir := config.BinDir(); dir != filepath.Join(config.RootDir, "lib/vendor/bin") {
+		t.Errorf("unexpected bin dir: %s", dir)
+	}
+}
@@ -0,0 +1,167 @@
+package composer
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/i582/go-composer.json/internal/version"
+)
+
+// CodeInvalidSuggest is the code of the errors reported by CheckSuggest.
+const CodeInvalidSuggest = "invalid-suggest"
+
+// CheckSuggest is a check for Config.AddCheckProvider that reports
+// suggest entries whose values are empty or look like version
+// constraints instead of a human-readable reason.
+//
+// See CheckProviderFunc
+func CheckSuggest(c *Config) []*ConfigError {
+	var errors []*ConfigError
+
+	for _, name := range sortedKeys(c.Suggest) {
+		reason := strings.TrimSpace(c.Suggest[name])
+
+		var msg string
+		switch {
+		case reason == "":
+			msg = fmt.Sprintf("suggest: reason for %s is empty", name)
+		case isConstraintLike(reason):
+			msg = fmt.Sprintf("suggest: reason for %s must be a human-readable text, not a version constraint '%s'", name, reason)
+		default:
+			continue
+		}
+
+		errors = append(errors, &ConfigError{
+			Msg:      msg,
+			Critical: false,
+			Code:     CodeInvalidSuggest,
+			Pointer:  "/suggest/" + escapePointer(name),
+		})
+	}
+
+	return errors
+}
+
+// isConstraintLike reports whether the text is a version constraint.
+func isConstraintLike(text string) bool {
+	if text == "self.version" {
+		return true
+	}
+
+	_, err := version.NewConstraint(text)
+	return err == nil
+}
+
+// Suggestion is a package suggested by the installed packages,
+// see Lock.Suggestions and Installed.Suggestions.
+type Suggestion struct {
+	// Name is the name of the suggested package, for example, ext-intl.
+	Name string `json:"name"`
+	// Reason is the reason the package is suggested for.
+	Reason string `json:"reason"`
+	// SuggestedBy are the sorted names of the packages
+	// that suggest the package for the reason.
+	SuggestedBy []string `json:"suggested-by"`
+}
+
+// Suggestions is the list of packages suggested by the installed packages.
+type Suggestions []Suggestion
+
+// Suggestions returns the packages suggested by the locked packages,
+// the dev packages are included if dev is true, see suggestionsOf.
+func (l *Lock) Suggestions(dev bool) Suggestions {
+	packages := l.Packages
+	if dev {
+		packages = l.AllPackages()
+	}
+	return suggestionsOf(packages)
+}
+
+// Suggestions returns the packages suggested by the installed packages,
+// see suggestionsOf.
+func (i *Installed) Suggestions() Suggestions {
+	packages := make([]LockPackage, 0, len(i.Packages))
+	for _, pkg := range i.Packages {
+		packages = append(packages, pkg.LockPackage)
+	}
+	return suggestionsOf(packages)
+}
+
+// suggestionsOf aggregates the suggest entries of the packages.
+//
+// As in composer, the packages that are already installed, or replaced
+// or provided by an installed package, are not suggested. The platform
+// packages, such as ext-intl, cannot be checked and are always suggested.
+// The packages suggesting the same package for the same reason are
+// grouped into a single suggestion.
+//
+// The suggestions are sorted by name and reason.
+func suggestionsOf(packages []LockPackage) Suggestions {
+	graph := newLockGraph(&Lock{Packages: packages})
+
+	type key struct {
+		name   string
+		reason string
+	}
+	grouped := map[key]*Suggestion{}
+
+	for _, pkg := range packages {
+		for name, reason := range pkg.Suggest {
+			if !IsPlatformPackage(name) && len(graph.resolve(name)) != 0 {
+				continue
+			}
+
+			k := key{name: strings.ToLower(name), reason: strings.TrimSpace(reason)}
+			suggestion, ok := grouped[k]
+			if !ok {
+				suggestion = &Suggestion{Name: name, Reason: k.reason}
+				grouped[k] = suggestion
+			}
+			suggestion.SuggestedBy = append(suggestion.SuggestedBy, pkg.Name)
+		}
+	}
+
+	var suggestions Suggestions
+	for _, suggestion := range grouped {
+		sort.Strings(suggestion.SuggestedBy)
+		suggestions = append(suggestions, *suggestion)
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Name != suggestions[j].Name {
+			return suggestions[i].Name < suggestions[j].Name
+		}
+		return suggestions[i].Reason < suggestions[j].Reason
+	})
+
+	return suggestions
+}
+
+// JSON returns the suggestions as a JSON array.
+func (s Suggestions) JSON() ([]byte, error) {
+	if s == nil {
+		s = Suggestions{}
+	}
+	return json.MarshalIndent(s, "", "  ")
+}
+
+// Text returns the suggestions as the lines of text,
+// one per suggestion, as composer prints them after install.
+//
+// Example:
+//
+//	ext-intl: For the localized formatting (suggested by symfony/intl, twig/intl-extra)
+func (s Suggestions) Text() string {
+	var b strings.Builder
+	for _, suggestion := range s {
+		b.WriteString(suggestion.Name)
+		if suggestion.Reason != "" {
+			b.WriteString(": ")
+			b.WriteString(suggestion.Reason)
+		}
+		fmt.Fprintf(&b, " (suggested by %s)\n", strings.Join(suggestion.SuggestedBy, ", "))
+	}
+	return b.String()
+}
@@ -0,0 +1,76 @@
+package composer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCheckSuggest(t *testing.T) {
+	config := &Config{
+		Suggest: map[string]string{
+			"monolog/monolog": "Allows more advanced logging of the application flow",
+			"ext-xml":         "Needed to support XML format in class Foo",
+			"foo/bar":         "^1.0",
+			"foo/baz":         "dev-master",
+			"foo/qux":         " ",
+			"foo/version":     "1.0 version is required",
+		},
+	}
+
+	var pointers []string
+	for _, err := range CheckSuggest(config) {
+		pointers = append(pointers, err.Pointer)
+	}
+
+	expected := []string{"/suggest/foo~1bar", "/suggest/foo~1baz", "/suggest/foo~1qux"}
+	if !reflect.DeepEqual(pointers, expected) {
+		t.Errorf("expected errors for %v, got %v", expected, pointers)
+	}
+}
+
+func TestSuggestions(t *testing.T) {
+	lock, err := ParseLock([]byte(`{
+		"packages": [
+			{"name": "symfony/intl", "suggest": {"ext-intl": "For the localized formatting", "psr/log": "To log"}},
+			{"name": "twig/intl-extra", "suggest": {"ext-intl": " For the localized formatting "}},
+			{"name": "monolog/monolog", "provide": {"psr/log-implementation": "3.0.0"}, "suggest": {
+				"psr/log-implementation": "To log",
+				"ext-intl": "For the translations",
+				"aws/aws-sdk-php": "Allow sending log messages to AWS services like DynamoDB"
+			}},
+			{"name": "psr/log"}
+		],
+		"packages-dev": [
+			{"name": "phpunit/phpunit", "suggest": {"ext-xdebug": "For the coverage"}}
+		]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Suggestions{
+		{Name: "aws/aws-sdk-php", Reason: "Allow sending log messages to AWS services like DynamoDB", SuggestedBy: []string{"monolog/monolog"}},
+		{Name: "ext-intl", Reason: "For the localized formatting", SuggestedBy: []string{"symfony/intl", "twig/intl-extra"}},
+		{Name: "ext-intl", Reason: "For the translations", SuggestedBy: []string{"monolog/monolog"}},
+	}
+	if suggestions := lock.Suggestions(false); !reflect.DeepEqual(suggestions, expected) {
+		t.Errorf("unexpected suggestions: %+v", suggestions)
+	}
+
+	suggestions := lock.Suggestions(true)
+	if len(suggestions) != 4 || suggestions[3].Name != "ext-xdebug" {
+		t.Errorf("expected the suggestions of the dev packages, got %+v", suggestions)
+	}
+
+	text := suggestions[:2].Text()
+	expectedText := "aws/aws-sdk-php: Allow sending log messages to AWS services like DynamoDB (suggested by monolog/monolog)\n" +
+		"ext-intl: For the localized formatting (suggested by symfony/intl, twig/intl-extra)\n"
+	if text != expectedText {
+		t.Errorf("unexpected text:\n%s", text)
+	}
+
+	installed := &Installed{Packages: []InstalledPackage{{LockPackage: lock.PackagesDev[0]}}}
+	if suggestions := installed.Suggestions(); len(suggestions) != 1 || suggestions[0].Name != "ext-xdebug" {
+		t.Errorf("unexpected installed suggestions: %+v", suggestions)
+	}
+}
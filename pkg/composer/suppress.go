@@ -0,0 +1,90 @@
+package composer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Suppression describes an error suppressed in the config itself.
+//
+// Suppressions are read from the extra."composer-check".ignore list,
+// each element of which is either a rule code or an object with
+// the rule code and a JSON pointer to the suppressed place.
+//
+// Example:
+//
+//	"extra": {
+//	  "composer-check": {
+//	    "ignore": [
+//	      "invalid-version",
+//	      {"rule": "my-rule", "pointer": "/require/foo~1bar"}
+//	    ]
+//	  }
+//	}
+type Suppression struct {
+	Rule string `json:"rule"`
+	// Pointer is a JSON pointer, if it is empty,
+	// the rule is suppressed for the entire config.
+	Pointer string `json:"pointer"`
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface
+// for both the string and the object forms.
+func (s *Suppression) UnmarshalJSON(data []byte) error {
+	var rule string
+	if err := json.Unmarshal(data, &rule); err == nil {
+		s.Rule = rule
+		return nil
+	}
+
+	type plain Suppression
+	var res plain
+	if err := json.Unmarshal(data, &res); err != nil {
+		return fmt.Errorf("suppression must be a rule code or an object with rule and pointer")
+	}
+
+	*s = Suppression(res)
+	return nil
+}
+
+// Matches reports whether the suppression applies to the error.
+//
+// An error matches if its code is equal to the rule and its pointer
+// is equal to the suppression pointer or is nested in it.
+func (s Suppression) Matches(err *ConfigError) bool {
+	if s.Rule == "" || s.Rule != err.Code {
+		return false
+	}
+	if s.Pointer == "" || s.Pointer == err.Pointer {
+		return true
+	}
+	return strings.HasPrefix(err.Pointer, s.Pointer+"/")
+}
+
+// IsSuppressed reports whether the error is suppressed in the config.
+func (c *Config) IsSuppressed(err *ConfigError) bool {
+	for _, s := range c.Suppressions {
+		if s.Matches(err) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseSuppressions reads the extra."composer-check".ignore list.
+func parseSuppressions(data []byte, unmarshal func([]byte, interface{}) error) ([]Suppression, error) {
+	var raw struct {
+		Extra struct {
+			ComposerCheck struct {
+				Ignore []Suppression `json:"ignore"`
+			} `json:"composer-check"`
+		} `json:"extra"`
+	}
+
+	if err := unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("extra.composer-check.ignore: %v", err)
+	}
+
+	return raw.Extra.ComposerCheck.Ignore, nil
+}
@@ -0,0 +1,39 @@
+package composer
+
+import (
+	"testing"
+)
+
+func TestSuppressions(t *testing.T) {
+	data := []byte(`{
+		"version": "1.0",
+		"extra": {
+			"composer-check": {
+				"ignore": [
+					"invalid-version",
+					{"rule": "my-rule", "pointer": "/require"}
+				]
+			}
+		}
+	}`)
+
+	config, errs := NewConfigFromData(data, "composer.json")
+	if errs != nil {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	config.AddCheck(func(*Config) *ConfigError {
+		return &ConfigError{Msg: "suppressed", Code: "my-rule", Pointer: "/require/foo~1bar"}
+	})
+	config.AddCheck(func(*Config) *ConfigError {
+		return &ConfigError{Msg: "not suppressed", Code: "my-rule", Pointer: "/require-dev/foo~1bar"}
+	})
+
+	errs = config.CheckConfig()
+	if errs.Len() != 1 || errs.Errors[0].Msg != "not suppressed" {
+		t.Errorf("expected a single not suppressed error, got %v", errs)
+	}
+	if len(errs.Suppressed) != 1 || errs.Suppressed[0].Msg != "suppressed" {
+		t.Errorf("expected a single suppressed error, got %v", errs.Suppressed)
+	}
+}
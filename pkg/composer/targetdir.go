@@ -0,0 +1,55 @@
+package composer
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// CodeInvalidTargetDir is the code of the errors
+// reported for the target-dir field.
+const CodeInvalidTargetDir = "invalid-target-dir"
+
+// InstallPath returns the path the package is installed to in the
+// vendor dir, including the legacy target-dir, as composer does.
+//
+// Example:
+//
+//	name:        symfony/yaml
+//	target-dir:  Symfony/Component/Yaml
+//	InstallPath: vendor/symfony/yaml/Symfony/Component/Yaml
+func (c *Config) InstallPath(vendorDir string) string {
+	return filepath.Join(vendorDir, filepath.FromSlash(c.Name), filepath.FromSlash(c.TargetDir))
+}
+
+// RootAutoloadPath returns the autoload path of the config when it
+// is the root package. As in composer, if the path does not exist,
+// the target-dir prefix is removed from it, since the files of the
+// root package are not moved into the target dir.
+func (c *Config) RootAutoloadPath(path string) string {
+	if c.TargetDir == "" || filepath.IsAbs(path) || c.fileExists(path) {
+		return path
+	}
+
+	targetDir := strings.Trim(filepath.ToSlash(c.TargetDir), "/")
+	trimmed := strings.TrimLeft(filepath.ToSlash(path), "/")
+	if trimmed == targetDir {
+		return ""
+	}
+	if strings.HasPrefix(trimmed, targetDir+"/") {
+		return strings.TrimLeft(trimmed[len(targetDir):], "/")
+	}
+	return path
+}
+
+// rootAutoloadPaths returns the paths passed through RootAutoloadPath.
+func (c *Config) rootAutoloadPaths(paths []string) []string {
+	if c.TargetDir == "" {
+		return paths
+	}
+
+	res := make([]string, 0, len(paths))
+	for _, path := range paths {
+		res = append(res, c.RootAutoloadPath(path))
+	}
+	return res
+}
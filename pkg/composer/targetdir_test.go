@@ -0,0 +1,83 @@
+package composer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTargetDir(t *testing.T) {
+	config, errs := NewConfigFromData([]byte(`{
+		"name": "symfony/yaml",
+		"version": "2.0.0",
+		"target-dir": "Symfony/Component/Yaml"
+	}`), "composer.json")
+	if errs != nil {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	expected := filepath.Join("vendor", "symfony", "yaml", "Symfony", "Component", "Yaml")
+	if path := config.InstallPath("vendor"); path != expected {
+		t.Errorf("unexpected install path: %s", path)
+	}
+
+	tests := map[string]string{
+		"Symfony/Component/Yaml/Parser.php": "Parser.php",
+		"Symfony/Component/Yaml":            "",
+		"src/Other.php":                     "src/Other.php",
+	}
+	for path, expected := range tests {
+		if actual := config.RootAutoloadPath(path); actual != expected {
+			t.Errorf("RootAutoloadPath(%q) = %q, want %q", path, actual, expected)
+		}
+	}
+
+	dir := t.TempDir()
+	for _, sub := range []string{"lib", filepath.Join("Acme", "Foo", "src")} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "lib", "Bar.php"), []byte("<?php\nnamespace Acme\\Foo;\nclass Bar {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config, errs = NewConfigFromData([]byte(`{
+		"name": "acme/foo",
+		"version": "1.0.0",
+		"target-dir": "Acme/Foo",
+		"autoload": {"classmap": ["Acme/Foo/lib/", "Acme/Foo/src/"]}
+	}`), filepath.Join(dir, "composer.json"))
+	if errs != nil {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	// The existing path is kept, the missing one is stripped.
+	if path := config.RootAutoloadPath("Acme/Foo/src/"); path != "Acme/Foo/src/" {
+		t.Errorf("expected the existing path to be kept, got %q", path)
+	}
+	if path := config.RootAutoloadPath("Acme/Foo/lib/"); path != "lib/" {
+		t.Errorf("expected the target-dir to be stripped, got %q", path)
+	}
+	if errs := CheckAutoloadPaths(config); len(errs) != 0 {
+		t.Errorf("unexpected autoload errors: %v", errs)
+	}
+
+	classmap, err := config.Classmap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path := classmap[`Acme\Foo\Bar`]; filepath.ToSlash(path) != "lib/Bar.php" {
+		t.Errorf("unexpected classmap: %v", classmap)
+	}
+
+	_, errs = NewConfigFromData([]byte(`{
+		"version": "1.0.0",
+		"target-dir": "Acme/Foo",
+		"autoload": {"psr-4": {"Acme\\Foo\\": ""}}
+	}`), "composer.json")
+	if errs.Len() != 1 || errs.Errors[0].Code != CodeInvalidTargetDir {
+		t.Errorf("expected target-dir error, got %v", errs)
+	}
+}
@@ -0,0 +1,59 @@
+package composer
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// CodeInvalidType is the code of the errors reported for the type field.
+const CodeInvalidType = "invalid-type"
+
+// The package types known to composer.
+const (
+	// TypeLibrary is the default type, it copies the files to vendor.
+	TypeLibrary = "library"
+	// TypeProject denotes a project rather than a library,
+	// for example, application shells or distributions.
+	TypeProject = "project"
+	// TypeMetapackage is an empty package that contains requirements
+	// and will trigger their installation, but contains no files.
+	TypeMetapackage = "metapackage"
+	// TypeComposerPlugin is a package that provides an installer
+	// for other packages that have a custom type.
+	TypeComposerPlugin = "composer-plugin"
+)
+
+// knownTypes are the package types handled by composer itself.
+var knownTypes = map[string]bool{
+	TypeLibrary:        true,
+	TypeProject:        true,
+	TypeMetapackage:    true,
+	TypeComposerPlugin: true,
+}
+
+// customTypeRegexp matches the custom installer types like
+// symfony-bundle or wordpress-plugin.
+var customTypeRegexp = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)+$`)
+
+// IsKnownType reports whether the type is one of the types
+// handled by composer itself.
+func IsKnownType(typ string) bool {
+	return knownTypes[typ]
+}
+
+// IsCustomType reports whether the type has the vendor-type
+// form of custom installer types, for example, symfony-bundle.
+func IsCustomType(typ string) bool {
+	return !knownTypes[typ] && customTypeRegexp.MatchString(typ)
+}
+
+// ValidateType checks that the package type is either a known type
+// or a custom installer type. The empty type means library.
+func ValidateType(typ string) error {
+	if typ == "" || IsKnownType(typ) || IsCustomType(typ) {
+		return nil
+	}
+
+	return fmt.Errorf("type '%s' must be one of library, project, metapackage, composer-plugin "+
+		"or a custom installer type in the form vendor-type, e.g. symfony-bundle", typ)
+}
@@ -0,0 +1,33 @@
+package composer
+
+import (
+	"testing"
+)
+
+func TestValidateType(t *testing.T) {
+	tests := []struct {
+		Type  string
+		Valid bool
+	}{
+		{Type: "", Valid: true},
+		{Type: TypeLibrary, Valid: true},
+		{Type: TypeComposerPlugin, Valid: true},
+		{Type: "symfony-bundle", Valid: true},
+		{Type: "wordpress-plugin", Valid: true},
+		{Type: "Library", Valid: false},
+		{Type: "lib", Valid: false},
+		{Type: "symfony bundle", Valid: false},
+		{Type: "symfony-", Valid: false},
+	}
+
+	for _, tt := range tests {
+		if err := ValidateType(tt.Type); (err == nil) != tt.Valid {
+			t.Errorf("ValidateType(%q) = %v, want valid %v", tt.Type, err, tt.Valid)
+		}
+	}
+
+	_, errs := NewConfigFromData([]byte(`{"version": "1.0.0", "type": "Library"}`), "composer.json")
+	if errs.Len() != 1 || errs.Errors[0].Code != CodeInvalidType || errs.Errors[0].Critical {
+		t.Errorf("expected a non-critical type error, got %v", errs)
+	}
+}
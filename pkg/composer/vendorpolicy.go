@@ -0,0 +1,168 @@
+package composer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CodeVendorNotAllowed is the code of the errors reported by VendorPolicy.
+const CodeVendorNotAllowed = "vendor-not-allowed"
+
+// VendorPolicy restricts the vendors of the packages that may appear
+// anywhere in the dependency tree of the config, not only in its
+// direct requirements.
+//
+// Example:
+//
+//	policy := composer.VendorPolicy{
+//	  AllowedVendors: []string{"symfony", "psr", "my-company"},
+//	  Exceptions:     []string{"monolog/monolog"},
+//	}
+//	cfg.RegisterCheck(policy.Check)
+type VendorPolicy struct {
+	// AllowedVendors are the vendors whose packages are allowed,
+	// the part of the package name before the slash.
+	AllowedVendors []string
+	// Exceptions are the names of the packages allowed
+	// whatever their vendor.
+	Exceptions []string
+	// Dev enables the policy for the packages
+	// needed only by the require-dev section.
+	Dev bool
+}
+
+// VendorViolation is a locked package whose vendor is not allowed,
+// see VendorPolicy.Violations.
+type VendorViolation struct {
+	Package string
+	Vendor  string
+	// Paths are the dependency paths introducing the package, one for each
+	// requirement of the config the package is needed by. A path starts with
+	// the package satisfying the requirement and ends with the package itself.
+	Paths [][]string
+}
+
+// Violations returns the locked packages needed by the config whose vendors
+// are not allowed by the policy, sorted by name. As in Config.PackageOrigins,
+// requirements are resolved through the replace and provide sections of
+// the locked packages, and platform packages are skipped.
+func (p VendorPolicy) Violations(c *Config, lock *Lock) []VendorViolation {
+	return p.violations(c, NewLockGraph(lock))
+}
+
+func (p VendorPolicy) violations(c *Config, graph *LockGraph) []VendorViolation {
+	allowed := map[string]bool{}
+	for _, vendor := range p.AllowedVendors {
+		allowed[strings.ToLower(vendor)] = true
+	}
+	for _, name := range p.Exceptions {
+		allowed[strings.ToLower(name)] = true
+	}
+
+	require := map[string]string{}
+	for name, constraint := range c.Require {
+		require[name] = constraint
+	}
+	if p.Dev {
+		for name, constraint := range c.RequireDev {
+			require[name] = constraint
+		}
+	}
+
+	var roots []string
+	for key := range graph.graph.resolveAll(require) {
+		roots = append(roots, key)
+	}
+	sort.Strings(roots)
+
+	violations := map[string]*VendorViolation{}
+	for _, root := range roots {
+		for key, path := range graph.graph.paths(root) {
+			pkg := graph.graph.packages[key]
+			vendor := strings.ToLower(pkg.Name)
+			if idx := strings.Index(vendor, "/"); idx >= 0 {
+				vendor = vendor[:idx]
+			}
+			if allowed[vendor] || allowed[key] {
+				continue
+			}
+
+			violation, ok := violations[key]
+			if !ok {
+				violation = &VendorViolation{Package: pkg.Name, Vendor: vendor}
+				violations[key] = violation
+			}
+			violation.Paths = append(violation.Paths, path)
+		}
+	}
+
+	var res []VendorViolation
+	for _, violation := range violations {
+		res = append(res, *violation)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Package < res[j].Package
+	})
+	return res
+}
+
+// Check is a ContextCheck reporting each package of the lock of the
+// config whose vendor is not allowed, with the dependency paths that
+// introduce it. The config without a lock is not checked.
+//
+// See Config.RegisterCheck
+func (p VendorPolicy) Check(ctx *CheckContext) []*ConfigError {
+	graph, err := ctx.LockGraph()
+	if err != nil {
+		return nil
+	}
+
+	var errors []*ConfigError
+	for _, violation := range p.violations(ctx.Config, graph) {
+		paths := make([]string, 0, len(violation.Paths))
+		for _, path := range violation.Paths {
+			paths = append(paths, strings.Join(path, " -> "))
+		}
+
+		errors = append(errors, &ConfigError{
+			Msg:      fmt.Sprintf("vendor %s of the package %s is not allowed, it is required by: %s", violation.Vendor, violation.Package, strings.Join(paths, "; ")),
+			Critical: false,
+			Code:     CodeVendorNotAllowed,
+		})
+	}
+	return errors
+}
+
+// paths returns the shortest dependency paths from the root
+// package to each package it needs, including the root itself.
+func (g *lockGraph) paths(root string) map[string][]string {
+	paths := map[string][]string{
+		root: {g.packages[root].Name},
+	}
+
+	queue := []string{root}
+	for len(queue) != 0 {
+		key := queue[0]
+		queue = queue[1:]
+
+		pkg := g.packages[key]
+		for _, name := range sortedKeys(pkg.Require) {
+			if IsPlatformPackage(name) {
+				continue
+			}
+			for _, dep := range g.resolve(name) {
+				if _, ok := paths[dep]; ok {
+					continue
+				}
+
+				path := make([]string, len(paths[key]), len(paths[key])+1)
+				copy(path, paths[key])
+				paths[dep] = append(path, g.packages[dep].Name)
+				queue = append(queue, dep)
+			}
+		}
+	}
+
+	return paths
+}
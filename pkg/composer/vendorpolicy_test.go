@@ -0,0 +1,101 @@
+package composer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestVendorPolicy(t *testing.T) {
+	lock, err := ParseLock([]byte(`{
+		"packages": [
+			{"name": "symfony/console", "require": {"php": ">=8.1", "symfony/string": "^6.0", "psr/container-implementation": "^2.0"}},
+			{"name": "symfony/string", "require": {"acme/unicode": "^1.0"}},
+			{"name": "acme/unicode"},
+			{"name": "evil/container", "provide": {"psr/container-implementation": "2.0"}},
+			{"name": "my-company/app", "require": {"symfony/console": "^6.0", "acme/unicode": "^1.0"}}
+		],
+		"packages-dev": [
+			{"name": "phpunit/phpunit", "require": {"sebastian/diff": "^5.0"}},
+			{"name": "sebastian/diff"}
+		]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := &Config{
+		Require:    map[string]string{"my-company/app": "^1.0", "php": "^8.1"},
+		RequireDev: map[string]string{"phpunit/phpunit": "^10.0"},
+	}
+	policy := VendorPolicy{
+		AllowedVendors: []string{"Symfony", "my-company"},
+		Exceptions:     []string{"evil/container", "phpunit/phpunit"},
+	}
+
+	expected := []VendorViolation{
+		{
+			Package: "acme/unicode",
+			Vendor:  "acme",
+			Paths:   [][]string{{"my-company/app", "acme/unicode"}},
+		},
+	}
+	if violations := policy.Violations(config, lock); !reflect.DeepEqual(violations, expected) {
+		t.Errorf("unexpected violations: %+v", violations)
+	}
+
+	policy.Dev = true
+	policy.Exceptions = []string{"phpunit/phpunit"}
+	config.RequireDev["acme/unicode"] = "^1.0"
+
+	var got []string
+	for _, violation := range policy.Violations(config, lock) {
+		for _, path := range violation.Paths {
+			got = append(got, strings.Join(path, " -> "))
+		}
+	}
+	expectedPaths := []string{
+		"acme/unicode",
+		"my-company/app -> acme/unicode",
+		"my-company/app -> symfony/console -> evil/container",
+		"phpunit/phpunit -> sebastian/diff",
+	}
+	if !reflect.DeepEqual(got, expectedPaths) {
+		t.Errorf("unexpected paths:\n%v\nexpected:\n%v", got, expectedPaths)
+	}
+}
+
+func TestVendorPolicyCheck(t *testing.T) {
+	dir, err := ioutil.TempDir("", "composer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	composerJson := `{"require": {"my/app": "^1.0"}}`
+	lockJson := `{"packages": [{"name": "my/app", "require": {"other/lib": "^1.0"}}, {"name": "other/lib"}]}`
+	for file, content := range map[string]string{"composer.json": composerJson, "composer.lock": lockJson} {
+		if err := ioutil.WriteFile(filepath.Join(dir, file), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config, errs := NewConfigFromFile(filepath.Join(dir, "composer.json"))
+	if errs != nil && hasCriticalError(errs) {
+		t.Fatal(errs)
+	}
+	config.RegisterCheck(VendorPolicy{AllowedVendors: []string{"my"}}.Check)
+
+	var found []*ConfigError
+	for _, err := range config.CheckConfig().Errors {
+		if err.Code == CodeVendorNotAllowed {
+			found = append(found, err)
+		}
+	}
+	if len(found) != 1 || !strings.Contains(found[0].Msg, "other/lib is not allowed, it is required by: my/app -> other/lib") {
+		t.Errorf("unexpected errors: %v", found)
+	}
+}
@@ -0,0 +1,239 @@
+package composer
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// CodeVirtualRootConflict is the code of the errors
+// reported by ComposeVirtualRoot.
+const CodeVirtualRootConflict = "virtual-root-conflict"
+
+// ComposeVirtualRoot returns a synthetic root config that merges the
+// requirements, repositories and autoload sections of the passed configs.
+//
+// This is useful when several applications share a single vendor dir.
+// If configs require the same package with different constraints, or map
+// the same namespace to different paths, an error is reported for each
+// such conflict and the value from the first config is kept.
+//
+// Requirements on the merged packages themselves are dropped, since
+// they are a part of the virtual root. Conflicts are merged, so that
+// a package conflicts with the root if it conflicts with any config,
+// replaces and provides are merged like requirements. A package required
+// by one config and required for development by another is installed
+// as a production dependency, so their constraints must match too.
+//
+// The autoload paths and the urls of local repositories are relative
+// to the root of each config, so they are rebased onto Config.RootDir
+// and the returned config does not share any of them with the passed ones.
+func ComposeVirtualRoot(configs ...*Config) (*Config, *ConfigErrors) {
+	root := &Config{
+		Type:       "project",
+		Require:    map[string]string{},
+		RequireDev: map[string]string{},
+		Conflict:   map[string]string{},
+		Replace:    map[string]string{},
+		Provide:    map[string]string{},
+		Autoload: Autoload{
+			Psr4: Psr4{},
+		},
+		AutoloadDev: Autoload{
+			Psr4: Psr4{},
+		},
+	}
+	errors := &ConfigErrors{Config: root}
+
+	members := make(map[string]bool, len(configs))
+	for _, config := range configs {
+		if config.Name != "" {
+			members[config.Name] = true
+		}
+	}
+
+	requireOwners := map[string]*Config{}
+	requireDevOwners := map[string]*Config{}
+	replaceOwners := map[string]*Config{}
+	provideOwners := map[string]*Config{}
+	psr4Owners := map[string]*Config{}
+	psr4DevOwners := map[string]*Config{}
+	repos := map[string]bool{}
+
+	for _, config := range configs {
+		mergeRequires(errors, "require", root.Require, requireOwners, config, config.Require, members)
+		mergeRequires(errors, "require-dev", root.RequireDev, requireDevOwners, config, config.RequireDev, members)
+		mergeConflicts(root.Conflict, config.Conflict)
+		mergeRequires(errors, "replace", root.Replace, replaceOwners, config, config.Replace, nil)
+		mergeRequires(errors, "provide", root.Provide, provideOwners, config, config.Provide, nil)
+		mergePsr4(errors, "autoload", root.Autoload.Psr4, psr4Owners, config, config.Autoload.Psr4)
+		mergePsr4(errors, "autoload-dev", root.AutoloadDev.Psr4, psr4DevOwners, config, config.AutoloadDev.Psr4)
+
+		root.Autoload.Files = append(root.Autoload.Files, rebasePaths(config.RootDir, config.Autoload.Files)...)
+		root.AutoloadDev.Files = append(root.AutoloadDev.Files, rebasePaths(config.RootDir, config.AutoloadDev.Files)...)
+		root.Autoload.Classmap = append(root.Autoload.Classmap, rebasePaths(config.RootDir, config.Autoload.Classmap)...)
+		root.AutoloadDev.Classmap = append(root.AutoloadDev.Classmap, rebasePaths(config.RootDir, config.AutoloadDev.Classmap)...)
+		root.Autoload.ExcludeFromClassmap = append(root.Autoload.ExcludeFromClassmap, rebasePaths(config.RootDir, config.Autoload.ExcludeFromClassmap)...)
+		root.AutoloadDev.ExcludeFromClassmap = append(root.AutoloadDev.ExcludeFromClassmap, rebasePaths(config.RootDir, config.AutoloadDev.ExcludeFromClassmap)...)
+
+		for _, repo := range config.Reps {
+			copied := copyRepo(repo)
+			if copied.isLocal() {
+				copied.Url = repo.ResolvedUrl(config.RootDir)
+				copied.Resolved = true
+			}
+
+			// The same repository may be written differently in each
+			// config, for example, as ../lib and ../../app/lib.
+			key := string(copied.Kind()) + " " + copied.NormalizedUrl()
+			if repos[key] {
+				continue
+			}
+			repos[key] = true
+			root.Reps = append(root.Reps, copied)
+		}
+	}
+
+	// A package required in both sections is installed as
+	// a production dependency.
+	for _, name := range sortedKeys(root.RequireDev) {
+		constraint, ok := root.Require[name]
+		if !ok {
+			continue
+		}
+
+		devConstraint := root.RequireDev[name]
+		if requireOwners[name] != requireDevOwners[name] && constraint != devConstraint {
+			errors.Add(&ConfigError{
+				Msg: fmt.Sprintf("require-dev: package %s has constraint '%s' in require of %s and '%s' in require-dev of %s",
+					name, constraint, requireOwners[name].Path, devConstraint, requireDevOwners[name].Path),
+				Critical: true,
+				Code:     CodeVirtualRootConflict,
+				Pointer:  "/require-dev/" + escapePointer(name),
+			})
+		}
+		delete(root.RequireDev, name)
+	}
+
+	if errors.Len() != 0 {
+		return root, errors
+	}
+
+	return root, nil
+}
+
+func mergeRequires(errors *ConfigErrors, section string, dst map[string]string, owners map[string]*Config, config *Config, src map[string]string, members map[string]bool) {
+	for _, name := range sortedKeys(src) {
+		if members[name] {
+			continue
+		}
+
+		constraint := src[name]
+		existing, ok := dst[name]
+		if !ok {
+			dst[name] = constraint
+			owners[name] = config
+			continue
+		}
+
+		if existing != constraint {
+			errors.Add(&ConfigError{
+				Msg: fmt.Sprintf("%s: package %s has constraint '%s' in %s and '%s' in %s",
+					section, name, existing, owners[name].Path, constraint, config.Path),
+				Critical: true,
+				Code:     CodeVirtualRootConflict,
+				Pointer:  "/" + section + "/" + escapePointer(name),
+			})
+		}
+	}
+}
+
+func mergeConflicts(dst map[string]string, src map[string]string) {
+	for _, name := range sortedKeys(src) {
+		constraint := src[name]
+		existing, ok := dst[name]
+		switch {
+		case !ok:
+			dst[name] = constraint
+		case existing != constraint:
+			dst[name] = existing + " || " + constraint
+		}
+	}
+}
+
+func mergePsr4(errors *ConfigErrors, section string, dst Psr4, owners map[string]*Config, config *Config, src Psr4) {
+	for _, namespace := range src.Prefixes() {
+		paths := rebasePaths(config.RootDir, src[namespace])
+		existing, ok := dst[namespace]
+		if !ok {
+			dst[namespace] = paths
+			owners[namespace] = config
+			continue
+		}
+
+		if strings.Join(existing, "\x00") == strings.Join(paths, "\x00") {
+			continue
+		}
+
+		errors.Add(&ConfigError{
+			Msg: fmt.Sprintf("%s: namespace %s is mapped to '%s' in %s and to '%s' in %s",
+				section, namespace, strings.Join(existing, "', '"), owners[namespace].Path, strings.Join(paths, "', '"), config.Path),
+			Critical: true,
+			Code:     CodeVirtualRootConflict,
+			Pointer:  "/" + section + "/psr-4/" + escapePointer(namespace),
+		})
+	}
+}
+
+// rebasePaths returns a copy of the paths relative to rootDir
+// joined with it, absolute paths are kept as is.
+//
+// The trailing slash is kept, since it is significant for psr-4 paths.
+func rebasePaths(rootDir string, paths []string) []string {
+	if paths == nil {
+		return nil
+	}
+
+	res := make([]string, 0, len(paths))
+	for _, p := range paths {
+		res = append(res, rebasePath(rootDir, p))
+	}
+	return res
+}
+
+func rebasePath(rootDir string, p string) string {
+	if rootDir == "" || filepath.IsAbs(filepath.FromSlash(p)) {
+		return p
+	}
+
+	res := path.Join(filepath.ToSlash(rootDir), p)
+	if strings.HasSuffix(p, "/") && !strings.HasSuffix(res, "/") {
+		res += "/"
+	}
+	return res
+}
+
+// copyRepo returns a copy of the repository that does not
+// share the options with the original one.
+func copyRepo(repo *ConfigRepo) *ConfigRepo {
+	copied := *repo
+	if repo.Options != nil {
+		options := *repo.Options
+		options.HTTP.Header = append(options.HTTP.Header[:0:0], options.HTTP.Header...)
+		copied.Options = &options
+	}
+	return &copied
+}
+
+// sortedKeys returns the keys of the map in sorted order,
+// so that the errors are reported in a stable order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,73 @@
+package composer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestComposeVirtualRoot(t *testing.T) {
+	first := &Config{
+		Name:     "app/first",
+		Path:     "/apps/first/composer.json",
+		RootDir:  "/apps/first",
+		Require:  map[string]string{"php": "^7.4", "foo/bar": "^1.0", "app/second": "*", "foo/qux": "^1.0"},
+		Conflict: map[string]string{"foo/old": "2.0.0"},
+		Reps:     []*ConfigRepo{{Type: "path", Url: "../lib"}},
+		Autoload: Autoload{
+			Psr4:     Psr4{`First\`: {"src/"}, `Lib\`: {"../lib/src/"}},
+			Classmap: []string{"legacy/"},
+		},
+	}
+	second := &Config{
+		Name:       "app/second",
+		Path:       "/apps/second/composer.json",
+		RootDir:    "/apps/second",
+		Require:    map[string]string{"php": "^7.4", "foo/bar": "^2.0"},
+		RequireDev: map[string]string{"foo/baz": "^1.0", "php": "^7.4", "foo/qux": "^2.0"},
+		Conflict:   map[string]string{"foo/old": "<1.0"},
+		Reps:       []*ConfigRepo{{Type: "path", Url: "../../apps/lib"}},
+		Autoload: Autoload{
+			Psr4:  Psr4{`Second\`: {"src/"}, `Lib\`: {"/apps/lib/src/"}},
+			Files: []string{"functions.php"},
+		},
+	}
+
+	root, errs := ComposeVirtualRoot(first, second)
+	if errs.Len() != 2 || errs.Errors[0].Pointer != "/require/foo~1bar" || errs.Errors[1].Pointer != "/require-dev/foo~1qux" {
+		t.Fatalf("expected conflicts for foo/bar and foo/qux, got %v", errs)
+	}
+
+	if root.Require["foo/bar"] != "^1.0" || root.Require["php"] != "^7.4" {
+		t.Errorf("unexpected require: %v", root.Require)
+	}
+	if _, ok := root.Require["app/second"]; ok {
+		t.Errorf("requirement on a composed config must be dropped")
+	}
+	if len(root.RequireDev) != 1 || root.RequireDev["foo/baz"] != "^1.0" {
+		t.Errorf("unexpected require-dev: %v", root.RequireDev)
+	}
+	if len(root.Reps) != 1 || root.Reps[0].Url != "/apps/lib" {
+		t.Errorf("expected repositories to be resolved and deduplicated, got %v", root.Reps)
+	}
+	if root.Reps[0] == first.Reps[0] || first.Reps[0].Url != "../lib" {
+		t.Errorf("expected the repository to be copied")
+	}
+	if root.Conflict["foo/old"] != "2.0.0 || <1.0" {
+		t.Errorf("unexpected conflict: %v", root.Conflict)
+	}
+
+	expectedPsr4 := Psr4{
+		`First\`:  {"/apps/first/src/"},
+		`Second\`: {"/apps/second/src/"},
+		`Lib\`:    {"/apps/lib/src/"},
+	}
+	if !reflect.DeepEqual(root.Autoload.Psr4, expectedPsr4) {
+		t.Errorf("unexpected psr-4: %v", root.Autoload.Psr4)
+	}
+	if !reflect.DeepEqual(root.Autoload.Classmap, []string{"/apps/first/legacy/"}) {
+		t.Errorf("unexpected classmap: %v", root.Autoload.Classmap)
+	}
+	if !reflect.DeepEqual(root.Autoload.Files, []string{"/apps/second/functions.php"}) {
+		t.Errorf("unexpected files: %v", root.Autoload.Files)
+	}
+}
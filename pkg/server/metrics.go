@@ -0,0 +1,133 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/i582/go-composer.json/pkg/composer"
+)
+
+// maxCustomRules is the maximum number of the codes of custom checks
+// counted separately, the findings of other custom checks are counted
+// as the "other" rule, so that the number of series stays bounded.
+const maxCustomRules = 20
+
+// otherRule is the rule of the findings without a code
+// and of the custom checks beyond maxCustomRules.
+const otherRule = "other"
+
+// labelEscaper escapes the label values
+// as the Prometheus text format requires.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// metrics are the counters exposed at /metrics
+// in the Prometheus text exposition format.
+type metrics struct {
+	mu sync.Mutex
+
+	configsParsed int64
+	parseErrors   int64
+	findings      map[string]int64
+
+	// builtinRules are the codes of the built-in rules.
+	builtinRules map[string]bool
+	// customRules is the number of the codes of custom checks in findings.
+	customRules int
+}
+
+func newMetrics() *metrics {
+	m := &metrics{
+		findings:     map[string]int64{},
+		builtinRules: map[string]bool{},
+	}
+	for _, rule := range composer.Rules() {
+		m.builtinRules[rule.Code] = true
+	}
+	return m
+}
+
+// observeParse counts a loaded config and its critical loading errors.
+func (m *metrics) observeParse(errs *composer.ConfigErrors) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.configsParsed++
+	if errs != nil && hasCritical(errs) {
+		m.parseErrors++
+	}
+}
+
+// observeFindings counts the errors reported by the checks by rule.
+func (m *metrics) observeFindings(errs *composer.ConfigErrors) {
+	if errs == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, e := range errs.Errors {
+		m.findings[m.rule(e.Code)]++
+	}
+}
+
+// rule returns the rule label of the findings with the code.
+func (m *metrics) rule(code string) string {
+	if code == "" {
+		return otherRule
+	}
+	if m.builtinRules[code] {
+		return code
+	}
+	if _, ok := m.findings[code]; ok {
+		return code
+	}
+	if m.customRules >= maxCustomRules {
+		return otherRule
+	}
+	m.customRules++
+	return code
+}
+
+// writeTo writes the metrics in the Prometheus text format.
+func (m *metrics) writeTo(w *strings.Builder) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fmt.Fprintln(w, "# HELP composer_configs_parsed_total Number of composer.json files parsed.")
+	fmt.Fprintln(w, "# TYPE composer_configs_parsed_total counter")
+	fmt.Fprintf(w, "composer_configs_parsed_total %d\n", m.configsParsed)
+
+	fmt.Fprintln(w, "# HELP composer_parse_errors_total Number of composer.json files that failed to load.")
+	fmt.Fprintln(w, "# TYPE composer_parse_errors_total counter")
+	fmt.Fprintf(w, "composer_parse_errors_total %d\n", m.parseErrors)
+
+	fmt.Fprintln(w, "# HELP composer_check_findings_total Number of errors reported by the checks by rule.")
+	fmt.Fprintln(w, "# TYPE composer_check_findings_total counter")
+
+	rules := make([]string, 0, len(m.findings))
+	for rule := range m.findings {
+		rules = append(rules, rule)
+	}
+	sort.Strings(rules)
+
+	for _, rule := range rules {
+		fmt.Fprintf(w, "composer_check_findings_total{rule=\"%s\"} %d\n", labelEscaper.Replace(rule), m.findings[rule])
+	}
+}
+
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var b strings.Builder
+	s.metrics.writeTo(&b)
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	_, _ = w.Write([]byte(b.String()))
+}
@@ -0,0 +1,123 @@
+package server
+
+// openAPI is the OpenAPI description of the API.
+const openAPI = `{
+  "openapi": "3.0.3",
+  "info": {
+    "title": "go-composer.json",
+    "description": "Validation and checks of composer.json files.",
+    "version": "1.0.0"
+  },
+  "paths": {
+    "/validate": {
+      "post": {
+        "summary": "Load composer.json and return the loading errors.",
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"type": "object"}}}
+        },
+        "responses": {
+          "200": {
+            "description": "Loading result.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ErrorsResponse"}}}
+          },
+          "422": {
+            "description": "The passed config cannot be loaded.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ErrorsResponse"}}}
+          }
+        }
+      }
+    },
+    "/check": {
+      "post": {
+        "summary": "Load composer.json and run the checks configured for the server.",
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"type": "object"}}}
+        },
+        "responses": {
+          "200": {
+            "description": "Check result.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ErrorsResponse"}}}
+          },
+          "422": {
+            "description": "The passed config cannot be loaded.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ErrorsResponse"}}}
+          }
+        }
+      }
+    },
+    "/resolve-class": {
+      "post": {
+        "summary": "Resolve the path of a namespace using the psr-4 autoload sections.",
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ResolveClassRequest"}}}
+        },
+        "responses": {
+          "200": {
+            "description": "Resolving result.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ResolveClassResponse"}}}
+          },
+          "422": {
+            "description": "The passed config cannot be loaded.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ErrorsResponse"}}}
+          }
+        }
+      }
+    },
+    "/metrics": {
+      "get": {
+        "summary": "Counters of the server in the Prometheus text format.",
+        "responses": {
+          "200": {
+            "description": "Metrics.",
+            "content": {"text/plain": {"schema": {"type": "string"}}}
+          }
+        }
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "Error": {
+        "type": "object",
+        "properties": {
+          "message": {"type": "string"},
+          "critical": {"type": "boolean"},
+          "code": {"type": "string"},
+          "pointer": {"type": "string"}
+        },
+        "required": ["message", "critical"]
+      },
+      "ErrorsResponse": {
+        "type": "object",
+        "properties": {
+          "valid": {"type": "boolean"},
+          "errors": {"type": "array", "items": {"$ref": "#/components/schemas/Error"}},
+          "suppressed": {"type": "array", "items": {"$ref": "#/components/schemas/Error"}}
+        },
+        "required": ["valid", "errors", "suppressed"]
+      },
+      "ResolveClassRequest": {
+        "type": "object",
+        "properties": {
+          "config": {"type": "object", "description": "The content of composer.json."},
+          "namespace": {"type": "string"}
+        },
+        "required": ["config", "namespace"]
+      },
+      "ResolveClassResponse": {
+        "type": "object",
+        "properties": {
+          "found": {"type": "boolean"},
+          "path": {"type": "string", "description": "The first of the paths."},
+          "paths": {"type": "array", "items": {"type": "string"}},
+          "dev": {"type": "boolean"}
+        },
+        "required": ["found"]
+      }
+    }
+  }
+}
+`
@@ -0,0 +1,264 @@
+// Package server exposes the functionality of the composer
+// package over an HTTP+JSON API.
+//
+// The API is described by the OpenAPI document served at /openapi.json,
+// the counters of the server are exposed at /metrics in the Prometheus
+// text format.
+//
+// All endpoints taking a config respond with 200 if the config is loaded,
+// even with non-critical errors, and with 422 and the loading errors
+// if it cannot be loaded because of critical errors.
+//
+// Example:
+//
+//	srv := server.New(composer.CheckProviderFunc(composer.CheckPinnedRequirements))
+//	log.Fatal(http.ListenAndServe(":8080", srv))
+package server
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+
+	"github.com/i582/go-composer.json/pkg/composer"
+)
+
+// maxBodySize is the maximum size of the request body.
+const maxBodySize = 10 << 20
+
+// sandboxRoot is the default root dir of the configs, see Server.Root.
+var sandboxRoot = filepath.Join(string(filepath.Separator), "nonexistent", "go-composer-sandbox")
+
+// Server is an http.Handler serving the API.
+type Server struct {
+	// Checks are run for each config passed to /check.
+	Checks []composer.CheckProvider
+	// Root is the dir the configs are loaded from, the paths of the configs,
+	// such as autoload paths, are resolved relative to it, so it must not
+	// contain files the clients must not see. By default it is a dir that
+	// does not exist, rather than the working dir of the server.
+	//
+	// The configs are always loaded in read-only mode,
+	// see composer.LoadOptions.ReadOnly.
+	Root string
+
+	mux     *http.ServeMux
+	metrics *metrics
+}
+
+// New returns a new server that runs the passed checks on /check.
+func New(checks ...composer.CheckProvider) *Server {
+	s := &Server{
+		Checks:  checks,
+		Root:    sandboxRoot,
+		mux:     http.NewServeMux(),
+		metrics: newMetrics(),
+	}
+
+	s.mux.HandleFunc("/validate", s.handleValidate)
+	s.mux.HandleFunc("/check", s.handleCheck)
+	s.mux.HandleFunc("/resolve-class", s.handleResolveClass)
+	s.mux.HandleFunc("/openapi.json", s.handleOpenAPI)
+	s.mux.HandleFunc("/metrics", s.handleMetrics)
+
+	return s
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Error is a single error in the response.
+type Error struct {
+	Message  string `json:"message"`
+	Critical bool   `json:"critical"`
+	Code     string `json:"code,omitempty"`
+	Pointer  string `json:"pointer,omitempty"`
+}
+
+// ErrorsResponse is the response of /validate and /check.
+type ErrorsResponse struct {
+	Valid      bool    `json:"valid"`
+	Errors     []Error `json:"errors"`
+	Suppressed []Error `json:"suppressed"`
+}
+
+// ResolveClassRequest is the request of /resolve-class.
+type ResolveClassRequest struct {
+	Config    json.RawMessage `json:"config"`
+	Namespace string          `json:"namespace"`
+}
+
+// ResolveClassResponse is the response of /resolve-class.
+//
+// Path is the first of the Paths, a namespace
+// can be mapped to several paths.
+type ResolveClassResponse struct {
+	Found bool     `json:"found"`
+	Path  string   `json:"path,omitempty"`
+	Paths []string `json:"paths,omitempty"`
+	Dev   bool     `json:"dev,omitempty"`
+}
+
+func (s *Server) handleValidate(w http.ResponseWriter, r *http.Request) {
+	data, ok := readBody(w, r)
+	if !ok {
+		return
+	}
+
+	_, errs, ok := s.loadConfig(w, data)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, newErrorsResponse(errs))
+}
+
+func (s *Server) handleCheck(w http.ResponseWriter, r *http.Request) {
+	data, ok := readBody(w, r)
+	if !ok {
+		return
+	}
+
+	config, errs, ok := s.loadConfig(w, data)
+	if !ok {
+		return
+	}
+
+	for _, check := range s.Checks {
+		config.AddCheckProvider(check)
+	}
+
+	checkErrs := config.CheckConfig()
+	s.metrics.observeFindings(checkErrs)
+	if errs == nil {
+		errs = checkErrs
+	} else if checkErrs != nil {
+		errs.Errors = append(errs.Errors, checkErrs.Errors...)
+		errs.Suppressed = append(errs.Suppressed, checkErrs.Suppressed...)
+	}
+
+	writeJSON(w, http.StatusOK, newErrorsResponse(errs))
+}
+
+func (s *Server) handleResolveClass(w http.ResponseWriter, r *http.Request) {
+	data, ok := readBody(w, r)
+	if !ok {
+		return
+	}
+
+	var req ResolveClassRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	config, _, ok := s.loadConfig(w, req.Config)
+	if !ok {
+		return
+	}
+
+	var resp ResolveClassResponse
+	if paths, found := config.Autoload.Psr4PathForNamespace(req.Namespace); found {
+		resp = ResolveClassResponse{Found: true, Paths: paths}
+	} else if paths, found := config.AutoloadDev.Psr4PathForNamespace(req.Namespace); found {
+		resp = ResolveClassResponse{Found: true, Paths: paths, Dev: true}
+	}
+	if len(resp.Paths) > 0 {
+		resp.Path = resp.Paths[0]
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(openAPI))
+}
+
+// loadConfig loads the config from the data in the root of the server,
+// if the config has critical errors, they are written as the response
+// with 422 status.
+func (s *Server) loadConfig(w http.ResponseWriter, data []byte) (*composer.Config, *composer.ConfigErrors, bool) {
+	config, errs := composer.NewConfigFromDataWithOptions(data, filepath.Join(s.Root, "composer.json"), composer.LoadOptions{ReadOnly: true})
+	s.metrics.observeParse(errs)
+	if errs != nil && hasCritical(errs) {
+		writeJSON(w, http.StatusUnprocessableEntity, newErrorsResponse(errs))
+		return nil, nil, false
+	}
+	return config, errs, true
+}
+
+// readBody reads the body of a POST request,
+// if the request is invalid, an error response is written.
+func readBody(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return nil, false
+	}
+
+	data, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+
+	return data, true
+}
+
+func newErrorsResponse(errs *composer.ConfigErrors) ErrorsResponse {
+	resp := ErrorsResponse{
+		Valid:      true,
+		Errors:     []Error{},
+		Suppressed: []Error{},
+	}
+	if errs == nil {
+		return resp
+	}
+
+	for _, e := range errs.Errors {
+		resp.Errors = append(resp.Errors, newError(e))
+		if e.Critical {
+			resp.Valid = false
+		}
+	}
+	for _, e := range errs.Suppressed {
+		resp.Suppressed = append(resp.Suppressed, newError(e))
+	}
+
+	return resp
+}
+
+func newError(e *composer.ConfigError) Error {
+	return Error{
+		Message:  e.Msg,
+		Critical: e.Critical,
+		Code:     e.Code,
+		Pointer:  e.Pointer,
+	}
+}
+
+func hasCritical(errs *composer.ConfigErrors) bool {
+	for _, e := range errs.Errors {
+		if e.Critical {
+			return true
+		}
+	}
+	return false
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,175 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/i582/go-composer.json/pkg/composer"
+)
+
+func TestServer(t *testing.T) {
+	srv := httptest.NewServer(New(composer.CheckProviderFunc(composer.CheckPinnedRequirements)))
+	defer srv.Close()
+
+	var errs ErrorsResponse
+	post(t, srv.URL+"/check", `{"version": "1.0.0", "require": {"foo/bar": "dev-master"}}`, &errs)
+	if !errs.Valid || len(errs.Errors) != 1 || errs.Errors[0].Code != composer.CodeUnpinnedRequirement {
+		t.Errorf("unexpected check response: %+v", errs)
+	}
+
+	for _, endpoint := range []string{"/validate", "/check"} {
+		errs = ErrorsResponse{}
+		status := post(t, srv.URL+endpoint, `{"name": `, &errs)
+		if status != http.StatusUnprocessableEntity || errs.Valid || len(errs.Errors) != 1 {
+			t.Errorf("unexpected %s response: %d %+v", endpoint, status, errs)
+		}
+	}
+
+	errs = ErrorsResponse{}
+	status := post(t, srv.URL+"/resolve-class", `{"config": {"name": 42}, "namespace": "App"}`, &errs)
+	if status != http.StatusUnprocessableEntity || errs.Valid {
+		t.Errorf("unexpected resolve-class response: %d %+v", status, errs)
+	}
+
+	var resolved ResolveClassResponse
+	post(t, srv.URL+"/resolve-class", `{
+		"config": {"version": "1.0.0", "autoload-dev": {"psr-4": {"App\\Tests\\": ["tests/", "fixtures/"]}}},
+		"namespace": "App\\Tests\\Unit"
+	}`, &resolved)
+	if !resolved.Found || !resolved.Dev || resolved.Path != "tests/" || len(resolved.Paths) != 2 {
+		t.Errorf("unexpected resolve-class response: %+v", resolved)
+	}
+
+	resp, err := http.Get(srv.URL + "/openapi.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var doc map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		t.Errorf("openapi.json is not valid json: %v", err)
+	}
+}
+
+func TestServerSandbox(t *testing.T) {
+	var loaded *composer.Config
+	srv := httptest.NewServer(New(composer.CheckProviderFunc(func(c *composer.Config) []*composer.ConfigError {
+		loaded = c
+		return nil
+	})))
+	defer srv.Close()
+
+	var errs ErrorsResponse
+	if status := post(t, srv.URL+"/check", `{"version": "1.0.0"}`, &errs); status != http.StatusOK {
+		t.Fatalf("unexpected status: %d", status)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded == nil || !loaded.ReadOnly || loaded.RootDir == wd {
+		t.Fatalf("expected a read-only config outside of the working dir, got %+v", loaded)
+	}
+	if _, err := os.Stat(loaded.RootDir); !os.IsNotExist(err) {
+		t.Errorf("expected the root %s not to exist, got %v", loaded.RootDir, err)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	srv := httptest.NewServer(New(composer.CheckProviderFunc(composer.CheckPinnedRequirements)))
+	defer srv.Close()
+
+	var errs ErrorsResponse
+	post(t, srv.URL+"/check", `{"version": "1.0.0", "require": {"foo/bar": "dev-master"}}`, &errs)
+	post(t, srv.URL+"/validate", `{"name": `, &errs)
+	post(t, srv.URL+"/resolve-class", `{"config": {"version": "1.0.0", "name": 42}, "namespace": "App"}`, &errs)
+
+	resp, err := http.Get(srv.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"composer_configs_parsed_total 3",
+		"composer_parse_errors_total 2",
+		`composer_check_findings_total{rule="unpinned-requirement"} 1`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(string(data), line+"\n") {
+			t.Errorf("metrics do not contain %q:\n%s", line, data)
+		}
+	}
+}
+
+func post(t *testing.T, url, body string, v interface{}) int {
+	t.Helper()
+
+	resp, err := http.Post(url, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode
+}
+
+func TestMetricsLabels(t *testing.T) {
+	srv := httptest.NewServer(New(composer.CheckProviderFunc(func(c *composer.Config) []*composer.ConfigError {
+		errs := []*composer.ConfigError{
+			{Msg: "escaped", Code: "my\\rule \"quoted\"\nnext"},
+			{Msg: "no code"},
+			{Msg: "built-in", Code: composer.CodeUnpinnedRequirement},
+		}
+		for i := 0; i < maxCustomRules+5; i++ {
+			errs = append(errs, &composer.ConfigError{Msg: "custom", Code: fmt.Sprintf("custom-%02d", i)})
+		}
+		return errs
+	})))
+	defer srv.Close()
+
+	var errs ErrorsResponse
+	post(t, srv.URL+"/check", `{"version": "1.0.0"}`, &errs)
+
+	resp, err := http.Get(srv.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		`composer_check_findings_total{rule="my\\rule \"quoted\"\nnext"} 1`,
+		`composer_check_findings_total{rule="unpinned-requirement"} 1`,
+		`composer_check_findings_total{rule="custom-18"} 1`,
+		`composer_check_findings_total{rule="other"} 7`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(string(data), line+"\n") {
+			t.Errorf("metrics do not contain %q:\n%s", line, data)
+		}
+	}
+	if strings.Contains(string(data), "custom-19") {
+		t.Errorf("expected the excess rules to be counted as other:\n%s", data)
+	}
+}